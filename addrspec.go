@@ -0,0 +1,278 @@
+package fields
+
+import (
+	"net/netip"
+	"strings"
+	"unicode/utf8"
+)
+
+// Length limits from RFC 5321 section 4.5.3.1
+const (
+	maxLocalLength  = 64
+	maxDomainLength = 255
+	maxLabelLength  = 63
+	maxEmailLength  = 254
+)
+
+// EmailRule names the part of the addr-spec grammar that an email violated
+type EmailRule string
+
+// Rules that can be reported by an EmailError
+const (
+	RuleAt            EmailRule = "at"
+	RuleEncoding      EmailRule = "encoding"
+	RuleLength        EmailRule = "length"
+	RuleDotAtom       EmailRule = "dot-atom"
+	RuleQuotedString  EmailRule = "quoted-string"
+	RuleDomain        EmailRule = "domain"
+	RuleDomainLiteral EmailRule = "domain-literal"
+)
+
+// EmailError is returned when an email cannot be parsed. Rule reports
+// which part of the grammar failed.
+type EmailError struct {
+	Rule   EmailRule
+	Reason string
+}
+
+// Error implements the error interface
+func (err EmailError) Error() string {
+	return err.Reason
+}
+
+func emailError(rule EmailRule, reason string) error {
+	return EmailError{Rule: rule, Reason: reason}
+}
+
+// ParseAddrSpec parses an RFC 5322 addr-spec, including the UTF-8
+// extensions of RFC 6531, into its local part and domain. Quoting is
+// removed from local parts that do not need it. Comments, folding white
+// space and the obsolete syntax are not accepted.
+func ParseAddrSpec(email string) (local, domain string, err error) {
+	if !utf8.ValidString(email) {
+		return "", "", emailError(RuleEncoding, "Emails must be valid UTF-8")
+	}
+	at := strings.LastIndexByte(email, '@')
+	if at == -1 {
+		return "", "", emailError(RuleAt, "Emails must contain a '@'")
+	}
+	local, domain = email[:at], email[at+1:]
+	if local == "" || domain == "" {
+		return "", "", emailError(
+			RuleAt, "Emails must be of the form 'user@domain'",
+		)
+	}
+
+	if local[0] == '"' {
+		if local, err = parseQuotedString(local); err != nil {
+			return "", "", err
+		}
+	} else {
+		if strings.IndexByte(local, '@') != -1 {
+			return "", "", emailError(
+				RuleAt, "Emails cannot have more than one '@'",
+			)
+		}
+		if err = checkDotAtom(local); err != nil {
+			return "", "", err
+		}
+	}
+	if len(local) > maxLocalLength {
+		return "", "", emailError(
+			RuleLength, "Email local parts cannot exceed 64 octets",
+		)
+	}
+
+	if domain[0] == '[' {
+		err = checkDomainLiteral(domain)
+	} else {
+		err = checkDomain(domain)
+	}
+	if err != nil {
+		return "", "", err
+	}
+	if len(local)+1+len(domain) > maxEmailLength {
+		return "", "", emailError(
+			RuleLength, "Emails cannot exceed 254 octets",
+		)
+	}
+	return local, domain, nil
+}
+
+// isAtext returns true if the rune is allowed in an unquoted atom. RFC 6531
+// extends atext with all non-ASCII UTF-8 characters.
+func isAtext(r rune) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		return true
+	case r >= utf8.RuneSelf:
+		return r != utf8.RuneError
+	}
+	return strings.ContainsRune("!#$%&'*+-/=?^_`{|}~", r)
+}
+
+// isQtext returns true if the rune is allowed unescaped inside a quoted
+// string. RFC 5321 qtextSMTP includes the space character.
+func isQtext(r rune) bool {
+	switch {
+	case r == 32 || r == 33 || (r >= 35 && r <= 91) || (r >= 93 && r <= 126):
+		return true
+	case r >= utf8.RuneSelf:
+		return r != utf8.RuneError
+	}
+	return false
+}
+
+func checkDotAtom(local string) error {
+	for _, atom := range strings.Split(local, ".") {
+		if atom == "" {
+			return emailError(
+				RuleDotAtom,
+				"Email local parts cannot begin or end with a dot or contain consecutive dots",
+			)
+		}
+		for _, r := range atom {
+			if !isAtext(r) {
+				return emailError(
+					RuleDotAtom,
+					"Email local parts contain an invalid character '"+string(r)+"' - use quotes to include it",
+				)
+			}
+		}
+	}
+	return nil
+}
+
+// parseQuotedString validates a quoted local part and returns it in its
+// simplest form: unquoted if it is a valid dot-atom, otherwise quoted with
+// only the necessary escapes.
+func parseQuotedString(quoted string) (string, error) {
+	if len(quoted) < 2 || quoted[len(quoted)-1] != '"' {
+		return "", emailError(
+			RuleQuotedString, "Email quoted local parts must end with a quote",
+		)
+	}
+	var content strings.Builder
+	escaped := false
+	for _, r := range quoted[1 : len(quoted)-1] {
+		if escaped {
+			if r < 32 || r > 126 {
+				return "", emailError(
+					RuleQuotedString,
+					"Email quoted local parts can only escape printable ASCII",
+				)
+			}
+			content.WriteRune(r)
+			escaped = false
+			continue
+		}
+		if r == '\\' {
+			escaped = true
+			continue
+		}
+		if !isQtext(r) {
+			return "", emailError(
+				RuleQuotedString,
+				"Email quoted local parts must escape quotes and backslashes",
+			)
+		}
+		content.WriteRune(r)
+	}
+	if escaped {
+		return "", emailError(
+			RuleQuotedString, "Email quoted local parts cannot end with a backslash",
+		)
+	}
+	return quoteLocalPart(content.String()), nil
+}
+
+// quoteLocalPart returns the local part unquoted if it is a valid
+// dot-atom, otherwise quoted with only the necessary escapes
+func quoteLocalPart(unquoted string) string {
+	if unquoted != "" && checkDotAtom(unquoted) == nil {
+		return unquoted
+	}
+	var out strings.Builder
+	out.WriteByte('"')
+	for _, r := range unquoted {
+		if r == '"' || r == '\\' {
+			out.WriteByte('\\')
+		}
+		out.WriteRune(r)
+	}
+	out.WriteByte('"')
+	return out.String()
+}
+
+// checkDomain validates a domain as a dot separated list of labels. Each
+// label is letters, digits and hyphens (or any non-ASCII character per
+// RFC 6531) and cannot begin or end with a hyphen.
+func checkDomain(domain string) error {
+	if len(domain) > maxDomainLength {
+		return emailError(RuleLength, "Email domains cannot exceed 255 octets")
+	}
+	for _, label := range strings.Split(domain, ".") {
+		if label == "" {
+			return emailError(
+				RuleDomain,
+				"Email domains cannot begin or end with a dot or contain consecutive dots",
+			)
+		}
+		if len(label) > maxLabelLength {
+			return emailError(
+				RuleLength, "Email domain labels cannot exceed 63 octets",
+			)
+		}
+		if label[0] == '-' || label[len(label)-1] == '-' {
+			return emailError(
+				RuleDomain, "Email domain labels cannot begin or end with a hyphen",
+			)
+		}
+		for _, r := range label {
+			switch {
+			case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			case r == '-':
+			case r >= utf8.RuneSelf && r != utf8.RuneError:
+			default:
+				return emailError(
+					RuleDomain,
+					"Email domains contain an invalid character '"+string(r)+"'",
+				)
+			}
+		}
+	}
+	return nil
+}
+
+// checkDomainLiteral validates an address literal such as [192.0.2.1] or
+// [IPv6:2001:db8::1]
+func checkDomainLiteral(domain string) error {
+	if len(domain) < 3 || domain[len(domain)-1] != ']' {
+		return emailError(
+			RuleDomainLiteral, "Email domain literals must be enclosed in brackets",
+		)
+	}
+	literal := domain[1 : len(domain)-1]
+	if tag, addr, ok := strings.Cut(literal, ":"); ok {
+		if !strings.EqualFold(tag, "IPv6") {
+			return emailError(
+				RuleDomainLiteral,
+				"Email domain literals only support the IPv6 tag",
+			)
+		}
+		ip, err := netip.ParseAddr(addr)
+		if err != nil || !ip.Is6() || ip.Zone() != "" {
+			return emailError(
+				RuleDomainLiteral, "Email domain literal is not a valid IPv6 address",
+			)
+		}
+		return nil
+	}
+	ip, err := netip.ParseAddr(literal)
+	if err != nil || !ip.Is4() {
+		return emailError(
+			RuleDomainLiteral, "Email domain literal is not a valid IPv4 address",
+		)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package fields
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseAddrSpec(t *testing.T) {
+	valid := []struct {
+		in, local, domain string
+	}{
+		{in: "a@example.com", local: "a", domain: "example.com"},
+		{in: "first.last+tag@example.com", local: "first.last+tag", domain: "example.com"},
+		{in: `"john@work"@example.com`, local: `"john@work"`, domain: "example.com"},
+		{in: `"john doe"@example.com`, local: `"john doe"`, domain: "example.com"},
+		{in: `"simple"@example.com`, local: "simple", domain: "example.com"},
+		{in: `"a\b"@example.com`, local: "ab", domain: "example.com"},
+		{in: `"a\"b"@example.com`, local: `"a\"b"`, domain: "example.com"},
+		{in: "user@[192.0.2.1]", local: "user", domain: "[192.0.2.1]"},
+		{in: "user@[IPv6:2001:db8::1]", local: "user", domain: "[IPv6:2001:db8::1]"},
+		{in: "用户@例子.广告", local: "用户", domain: "例子.广告"},
+		{in: "jörg@bücher.de", local: "jörg", domain: "bücher.de"},
+	}
+	for _, test := range valid {
+		local, domain, err := ParseAddrSpec(test.in)
+		if err != nil {
+			t.Errorf("ParseAddrSpec(%q) should not error: %s", test.in, err)
+			continue
+		}
+		if local != test.local || domain != test.domain {
+			t.Errorf(
+				"unexpected parse of %q: %s @ %s != %s @ %s",
+				test.in, local, domain, test.local, test.domain,
+			)
+		}
+	}
+
+	invalid := []struct {
+		in   string
+		rule EmailRule
+	}{
+		{in: "dachshundlover", rule: RuleAt},
+		{in: "k@r@j", rule: RuleAt},
+		{in: "a b@c", rule: RuleDotAtom},
+		{in: ".a@c", rule: RuleDotAtom},
+		{in: "a..b@c", rule: RuleDotAtom},
+		{in: `"a@c`, rule: RuleQuotedString},
+		{in: `"a"b"@c`, rule: RuleQuotedString},
+		{in: "a@-c.com", rule: RuleDomain},
+		{in: "a@c..com", rule: RuleDomain},
+		{in: "a@c_d.com", rule: RuleDomain},
+		{in: "a@[300.0.0.1]", rule: RuleDomainLiteral},
+		{in: "a@[IPv6:192.0.2.1]", rule: RuleDomainLiteral},
+		{in: "a@[192.0.2.1", rule: RuleDomainLiteral},
+		{in: strings.Repeat("a", 65) + "@c", rule: RuleLength},
+		{in: "a@" + strings.Repeat("c", 64) + ".com", rule: RuleLength},
+		{in: "a@\xff", rule: RuleEncoding},
+	}
+	for _, test := range invalid {
+		_, _, err := ParseAddrSpec(test.in)
+		var emailErr EmailError
+		if !errors.As(err, &emailErr) {
+			t.Errorf("ParseAddrSpec(%q) should return an EmailError", test.in)
+			continue
+		}
+		if emailErr.Rule != test.rule {
+			t.Errorf(
+				"unexpected rule for %q: %s != %s",
+				test.in, emailErr.Rule, test.rule,
+			)
+		}
+	}
+}
+
+func TestEmail_UnmarshalJSON(t *testing.T) {
+	var email Email
+	if err := json.Unmarshal([]byte(`" A@Example.com "`), &email); err != nil {
+		t.Fatalf("Unmarshal JSON should not error: %s", err)
+	}
+	if email != "a@example.com" {
+		t.Errorf("unexpected email: %s != a@example.com", email)
+	}
+
+	if err := json.Unmarshal([]byte(`"a b@c"`), &email); err == nil {
+		t.Errorf("Unmarshal JSON should error with an invalid email")
+	}
+	if email != "a@example.com" {
+		t.Errorf("email should be unchanged after an error")
+	}
+}
@@ -0,0 +1,156 @@
+package fields
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/aodin/sol"
+	"github.com/aodin/sol/types"
+)
+
+// CanonicalizationRule describes how a mail provider delivers addresses,
+// so that aliases of the same mailbox share a canonical form
+type CanonicalizationRule struct {
+	// Domain is the provider's primary domain, such as gmail.com
+	Domain string
+
+	// Aliases are domains that deliver to the same mailboxes as Domain,
+	// such as googlemail.com
+	Aliases []string
+
+	// Separator starts a sub-address tag that is stripped from the local
+	// part, such as "+" in user+tag. Empty if the provider has no tags.
+	Separator string
+
+	// IgnoreDots removes all dots from the local part
+	IgnoreDots bool
+}
+
+// Canonicalize returns the canonical local part and domain
+func (rule CanonicalizationRule) Canonicalize(local, domain string) (string, string) {
+	if rule.Separator != "" {
+		if i := strings.Index(local, rule.Separator); i > 0 {
+			local = local[:i]
+		}
+	}
+	if rule.IgnoreDots {
+		local = strings.Replace(local, ".", "", -1)
+	}
+	return local, rule.Domain
+}
+
+var canonicalRules = struct {
+	sync.RWMutex
+	domains map[string]CanonicalizationRule
+}{domains: make(map[string]CanonicalizationRule)}
+
+// RegisterCanonicalizationRule adds a rule for the rule's domain and all of
+// its aliases, replacing any existing rule for those domains
+func RegisterCanonicalizationRule(rule CanonicalizationRule) {
+	rule.Domain = strings.ToLower(rule.Domain)
+	canonicalRules.Lock()
+	defer canonicalRules.Unlock()
+	canonicalRules.domains[rule.Domain] = rule
+	for _, alias := range rule.Aliases {
+		canonicalRules.domains[strings.ToLower(alias)] = rule
+	}
+}
+
+// UnregisterCanonicalizationRule removes the rule registered for the given
+// domain or alias, along with all of the rule's other domains
+func UnregisterCanonicalizationRule(domain string) {
+	canonicalRules.Lock()
+	defer canonicalRules.Unlock()
+	rule, ok := canonicalRules.domains[strings.ToLower(domain)]
+	if !ok {
+		return
+	}
+	for name, other := range canonicalRules.domains {
+		if other.Domain == rule.Domain {
+			delete(canonicalRules.domains, name)
+		}
+	}
+}
+
+// CanonicalizationRuleFor returns the rule registered for the given domain
+func CanonicalizationRuleFor(domain string) (CanonicalizationRule, bool) {
+	canonicalRules.RLock()
+	defer canonicalRules.RUnlock()
+	rule, ok := canonicalRules.domains[strings.ToLower(domain)]
+	return rule, ok
+}
+
+func init() {
+	for _, rule := range []CanonicalizationRule{
+		{
+			Domain:     "gmail.com",
+			Aliases:    []string{"googlemail.com"},
+			Separator:  "+",
+			IgnoreDots: true,
+		},
+		{
+			Domain:    "outlook.com",
+			Separator: "+",
+		},
+		{
+			Domain:    "hotmail.com",
+			Separator: "+",
+		},
+		{
+			Domain:    "icloud.com",
+			Aliases:   []string{"me.com", "mac.com"},
+			Separator: "+",
+		},
+		{
+			Domain:    "proton.me",
+			Aliases:   []string{"protonmail.com", "protonmail.ch", "pm.me"},
+			Separator: "+",
+		},
+		{
+			Domain:    "fastmail.com",
+			Separator: "+",
+		},
+		{
+			Domain:    "yahoo.com",
+			Separator: "-",
+		},
+	} {
+		RegisterCanonicalizationRule(rule)
+	}
+}
+
+// Canonical returns the form of the email used for deduplication: the
+// normalized email with the registered CanonicalizationRule for its domain
+// applied. Quoted local parts and domains without a rule are only
+// normalized. The email itself is unchanged.
+func (email Email) Canonical() Email {
+	normalized, err := NormalizeEmail(string(email))
+	if err != nil {
+		return email
+	}
+	at := strings.LastIndexByte(normalized, '@')
+	local, domain := normalized[:at], normalized[at+1:]
+	if strings.HasPrefix(local, `"`) {
+		return Email(normalized)
+	}
+	rule, ok := CanonicalizationRuleFor(domain)
+	if !ok {
+		return Email(normalized)
+	}
+	local, domain = rule.Canonicalize(local, domain)
+	return Email(local + "@" + domain)
+}
+
+// CanonicalEmailColumn is a Modifier for a unique column that stores the
+// canonical form of an email. The string is the column name.
+type CanonicalEmailColumn string
+
+var _ sol.Modifier = CanonicalEmailColumn("")
+
+// Modify implements the sol.Modifier interface
+func (name CanonicalEmailColumn) Modify(table sol.Tabular) error {
+	if err := sol.Column(string(name), types.Text().NotNull()).Modify(table); err != nil {
+		return err
+	}
+	return sol.Unique(string(name)).Modify(table)
+}
@@ -0,0 +1,67 @@
+package fields
+
+import (
+	"testing"
+
+	sql "github.com/aodin/sol"
+)
+
+var CanonicalTests = sql.Table("canonical_tests",
+	Serial{},
+	CanonicalEmailColumn("canonical_email"),
+)
+
+func TestEmail_Canonical(t *testing.T) {
+	tests := []struct {
+		in, out string
+	}{
+		{in: "J.O.H.N+promo@gmail.com", out: "john@gmail.com"},
+		{in: "john@googlemail.com", out: "john@gmail.com"},
+		{in: "john+news@outlook.com", out: "john@outlook.com"},
+		{in: "john.doe-promo@yahoo.com", out: "john.doe@yahoo.com"},
+		{in: "john+tag@example.com", out: "john+tag@example.com"},
+		{in: "+tag@gmail.com", out: "+tag@gmail.com"},
+		{in: `"j doe"@gmail.com`, out: `"j doe"@gmail.com`},
+		{in: "not an email", out: "not an email"},
+	}
+	for _, test := range tests {
+		email := Email(test.in)
+		if canonical := email.Canonical(); string(canonical) != test.out {
+			t.Errorf(
+				"unexpected canonical email for %s: %s != %s",
+				test.in, canonical, test.out,
+			)
+		}
+		if string(email) != test.in {
+			t.Errorf("Canonical should not modify the email")
+		}
+	}
+
+	// Rules can be registered for other providers
+	RegisterCanonicalizationRule(CanonicalizationRule{
+		Domain:    "example.org",
+		Aliases:   []string{"example.net"},
+		Separator: "_",
+	})
+	t.Cleanup(func() { UnregisterCanonicalizationRule("example.org") })
+	if out := Email("a_b@example.net").Canonical(); out != "a@example.org" {
+		t.Errorf("unexpected canonical email: %s != a@example.org", out)
+	}
+}
+
+func TestUnregisterCanonicalizationRule(t *testing.T) {
+	RegisterCanonicalizationRule(CanonicalizationRule{
+		Domain:    "example.com",
+		Aliases:   []string{"example.edu"},
+		Separator: "+",
+	})
+	UnregisterCanonicalizationRule("example.edu")
+	for _, domain := range []string{"example.com", "example.edu"} {
+		if _, ok := CanonicalizationRuleFor(domain); ok {
+			t.Errorf("the rule for %s should be removed", domain)
+		}
+	}
+	if _, ok := CanonicalizationRuleFor("gmail.com"); !ok {
+		t.Errorf("other rules should not be removed")
+	}
+}
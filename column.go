@@ -0,0 +1,179 @@
+package fields
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aodin/sol/dialect"
+	"github.com/aodin/sol/types"
+)
+
+// ColumnOption configures the column Modifiers of this package, such as
+// EmailColumn. Each column supports only some of the options, and its
+// Modify method will error if given any others.
+type ColumnOption func(*columnOptions)
+
+type columnOptions struct {
+	notNull     bool
+	unique      bool
+	primaryKey  bool
+	check       bool
+	citext      bool
+	lowercase   bool
+	limit       int
+	schemes     []string
+	defaultExpr string
+	names       []string // The names of the options used
+}
+
+// use records the name of an option, so that unsupported options can be
+// reported
+func (opts *columnOptions) use(name string) {
+	opts.names = append(opts.names, name)
+}
+
+// supports returns an error if any option used is not in the supported
+// names
+func (opts columnOptions) supports(column string, supported ...string) error {
+	for _, name := range opts.names {
+		if !containsString(supported, name) {
+			return fmt.Errorf("%s columns do not support the %s option", column, name)
+		}
+	}
+	return nil
+}
+
+// NotNull adds a NOT NULL constraint to the column
+func NotNull() ColumnOption {
+	return func(opts *columnOptions) {
+		opts.use("NotNull")
+		opts.notNull = true
+	}
+}
+
+// Unique adds a UNIQUE constraint to the column
+func Unique() ColumnOption {
+	return func(opts *columnOptions) {
+		opts.use("Unique")
+		opts.unique = true
+	}
+}
+
+// PrimaryKey makes the column the primary key of its table
+func PrimaryKey() ColumnOption {
+	return func(opts *columnOptions) {
+		opts.use("PrimaryKey")
+		opts.primaryKey = true
+	}
+}
+
+// Default sets a server-side default for the column. The expression is
+// used as is, such as gen_random_uuid() or now().
+func Default(expression string) ColumnOption {
+	return func(opts *columnOptions) {
+		opts.use("Default")
+		opts.defaultExpr = expression
+	}
+}
+
+// Check adds a CHECK constraint that enforces the format of the column's
+// type, such as the '@' rules of an email
+func Check() ColumnOption {
+	return func(opts *columnOptions) {
+		opts.use("Check")
+		opts.check = true
+	}
+}
+
+// CheckSchemes adds a CHECK constraint that URLs begin with one of the
+// given schemes
+func CheckSchemes(schemes ...string) ColumnOption {
+	return func(opts *columnOptions) {
+		opts.use("CheckSchemes")
+		opts.check = true
+		opts.schemes = append(opts.schemes, schemes...)
+	}
+}
+
+// Limit sets the maximum length of a text column, which uses the VARCHAR
+// type instead of TEXT
+func Limit(limit int) ColumnOption {
+	return func(opts *columnOptions) {
+		opts.use("Limit")
+		opts.limit = limit
+	}
+}
+
+// Lowercase adds a CHECK constraint that text is lowercase. Combined with
+// Unique, it makes uniqueness case-insensitive, but writes of values that
+// were not lowercased first will fail the constraint rather than be caught
+// as duplicates.
+func Lowercase() ColumnOption {
+	return func(opts *columnOptions) {
+		opts.use("Lowercase")
+		opts.lowercase = true
+	}
+}
+
+// CIText uses the case-insensitive citext type for text columns. It requires
+// the Postgres citext extension.
+func CIText() ColumnOption {
+	return func(opts *columnOptions) {
+		opts.use("CIText")
+		opts.citext = true
+	}
+}
+
+// quoteIdentifier quotes a Postgres identifier, such as a column name
+func quoteIdentifier(name string) string {
+	return `"` + strings.Replace(name, `"`, `""`, -1) + `"`
+}
+
+func newColumnOptions(opts []ColumnOption) columnOptions {
+	var options columnOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+// columnType is a sol column type for Postgres types and constraints that
+// sol does not provide
+type columnType struct {
+	err         error // An error from the column's options
+	name        string
+	notNull     bool
+	defaultExpr string
+	primaryKey  bool
+	unique      bool
+	constraints []string
+}
+
+var _ types.Type = columnType{}
+
+// Create returns the column type and its constraints
+func (t columnType) Create(d dialect.Dialect) (string, error) {
+	if t.err != nil {
+		return "", t.err
+	}
+	if t.name == "" {
+		return "", fmt.Errorf("Column types must have a name")
+	}
+	parts := []string{t.name}
+	if t.notNull {
+		parts = append(parts, "NOT NULL")
+	}
+	if t.defaultExpr != "" {
+		parts = append(parts, "DEFAULT "+t.defaultExpr)
+	}
+	if t.primaryKey {
+		parts = append(parts, "PRIMARY KEY")
+	}
+	if t.unique {
+		parts = append(parts, "UNIQUE")
+	}
+	for _, constraint := range t.constraints {
+		parts = append(parts, "CHECK ("+constraint+")")
+	}
+	return strings.Join(parts, " "), nil
+}
@@ -0,0 +1,180 @@
+package fields
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/idna"
+)
+
+// Deliverability is the result of checking whether an email's domain
+// accepts mail
+type Deliverability int
+
+// Results returned by CheckDeliverable
+const (
+	// TemporaryFailure means the lookup failed and can be retried
+	TemporaryFailure Deliverability = iota
+	// NoSuchDomain means the domain has no MX, A or AAAA records
+	NoSuchDomain
+	// NullMX means the domain explicitly accepts no mail (RFC 7505)
+	NullMX
+	// DeliverableMX means the domain has at least one MX record
+	DeliverableMX
+	// DeliverableFallback means the domain has no MX records, but has an A
+	// or AAAA record that will receive mail (RFC 5321 section 5.1)
+	DeliverableFallback
+)
+
+// String returns a description of the result
+func (result Deliverability) String() string {
+	switch result {
+	case TemporaryFailure:
+		return "temporary failure"
+	case NoSuchDomain:
+		return "no such domain"
+	case NullMX:
+		return "null MX"
+	case DeliverableMX:
+		return "deliverable"
+	case DeliverableFallback:
+		return "deliverable by address fallback"
+	}
+	return "unknown"
+}
+
+// Deliverable returns true if the domain should accept mail
+func (result Deliverability) Deliverable() bool {
+	return result == DeliverableMX || result == DeliverableFallback
+}
+
+// Resolver performs the DNS lookups needed by CheckDeliverable. It is
+// satisfied by *net.Resolver.
+type Resolver interface {
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+var _ Resolver = &net.Resolver{}
+
+// CheckDeliverable looks up the mail hosts of the email's domain. A non-nil
+// error is returned with TemporaryFailure or if the email is invalid.
+func (email Email) CheckDeliverable(ctx context.Context, resolver Resolver) (Deliverability, error) {
+	_, domain, err := ParseAddrSpec(strings.TrimSpace(string(email)))
+	if err != nil {
+		return NoSuchDomain, err
+	}
+	// Address literals need no lookup
+	if strings.HasPrefix(domain, "[") {
+		return DeliverableFallback, nil
+	}
+	if domain, err = idna.Lookup.ToASCII(domain); err != nil {
+		return NoSuchDomain, err
+	}
+
+	records, err := resolver.LookupMX(ctx, domain)
+	if err != nil && !isNotFound(err) {
+		return TemporaryFailure, err
+	}
+	if len(records) == 1 && (records[0].Host == "." || records[0].Host == "") {
+		return NullMX, nil
+	}
+	if len(records) > 0 {
+		return DeliverableMX, nil
+	}
+
+	addrs, err := resolver.LookupIPAddr(ctx, domain)
+	if err != nil && !isNotFound(err) {
+		return TemporaryFailure, err
+	}
+	if len(addrs) > 0 {
+		return DeliverableFallback, nil
+	}
+	return NoSuchDomain, nil
+}
+
+func isNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
+
+type cachedLookup struct {
+	mx      []*net.MX
+	addrs   []net.IPAddr
+	err     error
+	expires time.Time
+}
+
+// CachedResolver wraps a Resolver and caches its answers for a TTL.
+// Not found answers are cached, but temporary failures are not. It is
+// safe for concurrent use.
+type CachedResolver struct {
+	resolver Resolver
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu    sync.Mutex
+	mx    map[string]cachedLookup
+	addrs map[string]cachedLookup
+}
+
+var _ Resolver = &CachedResolver{}
+
+// LookupMX implements the Resolver interface
+func (cache *CachedResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	if hit, ok := cache.get(cache.mx, name); ok {
+		return hit.mx, hit.err
+	}
+	mx, err := cache.resolver.LookupMX(ctx, name)
+	cache.set(cache.mx, name, cachedLookup{mx: mx, err: err})
+	return mx, err
+}
+
+// LookupIPAddr implements the Resolver interface
+func (cache *CachedResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	if hit, ok := cache.get(cache.addrs, host); ok {
+		return hit.addrs, hit.err
+	}
+	addrs, err := cache.resolver.LookupIPAddr(ctx, host)
+	cache.set(cache.addrs, host, cachedLookup{addrs: addrs, err: err})
+	return addrs, err
+}
+
+func (cache *CachedResolver) get(lookups map[string]cachedLookup, name string) (cachedLookup, bool) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	hit, ok := lookups[name]
+	if !ok {
+		return hit, false
+	}
+	if !cache.now().Before(hit.expires) {
+		delete(lookups, name)
+		return hit, false
+	}
+	return hit, true
+}
+
+func (cache *CachedResolver) set(lookups map[string]cachedLookup, name string, lookup cachedLookup) {
+	if lookup.err != nil && !isNotFound(lookup.err) {
+		return
+	}
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	lookup.expires = cache.now().Add(cache.ttl)
+	lookups[name] = lookup
+}
+
+// NewCachedResolver creates a new CachedResolver
+func NewCachedResolver(resolver Resolver, ttl time.Duration) *CachedResolver {
+	return &CachedResolver{
+		resolver: resolver,
+		ttl:      ttl,
+		now:      time.Now,
+		mx:       make(map[string]cachedLookup),
+		addrs:    make(map[string]cachedLookup),
+	}
+}
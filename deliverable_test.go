@@ -0,0 +1,112 @@
+package fields
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+// fakeResolver answers lookups from memory
+type fakeResolver struct {
+	mx    map[string][]*net.MX
+	addrs map[string][]net.IPAddr
+	fail  map[string]bool
+	calls int
+}
+
+func (r *fakeResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	r.calls++
+	if r.fail[name] {
+		return nil, &net.DNSError{Err: "timeout", Name: name, IsTimeout: true}
+	}
+	if mx, ok := r.mx[name]; ok {
+		return mx, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func (r *fakeResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	r.calls++
+	if addrs, ok := r.addrs[host]; ok {
+		return addrs, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+func TestEmail_CheckDeliverable(t *testing.T) {
+	resolver := &fakeResolver{
+		mx: map[string][]*net.MX{
+			"example.com":      {{Host: "mx.example.com.", Pref: 10}},
+			"null.example":     {{Host: ".", Pref: 0}},
+			"xn--bcher-kva.de": {{Host: "mx.xn--bcher-kva.de.", Pref: 10}},
+		},
+		addrs: map[string][]net.IPAddr{
+			"fallback.example": {{IP: net.ParseIP("192.0.2.1")}},
+		},
+		fail: map[string]bool{"slow.example": true},
+	}
+
+	tests := []struct {
+		email  Email
+		result Deliverability
+	}{
+		{email: "a@example.com", result: DeliverableMX},
+		{email: "a@bücher.de", result: DeliverableMX},
+		{email: "a@null.example", result: NullMX},
+		{email: "a@fallback.example", result: DeliverableFallback},
+		{email: "a@missing.example", result: NoSuchDomain},
+		{email: "a@slow.example", result: TemporaryFailure},
+		{email: "a@[192.0.2.1]", result: DeliverableFallback},
+	}
+	ctx := context.Background()
+	for _, test := range tests {
+		result, err := test.email.CheckDeliverable(ctx, resolver)
+		if result != test.result {
+			t.Errorf("unexpected result for %s: %s != %s", test.email, result, test.result)
+		}
+		if (result == TemporaryFailure) != (err != nil) {
+			t.Errorf("unexpected error for %s: %v", test.email, err)
+		}
+	}
+
+	if _, err := Email("a b@c").CheckDeliverable(ctx, resolver); err == nil {
+		t.Errorf("CheckDeliverable should error with an invalid email")
+	}
+}
+
+func TestCachedResolver(t *testing.T) {
+	fake := &fakeResolver{
+		mx:   map[string][]*net.MX{"example.com": {{Host: "mx.example.com."}}},
+		fail: map[string]bool{"slow.example": true},
+	}
+	now := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := NewCachedResolver(fake, time.Minute)
+	cache.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	Email("a@example.com").CheckDeliverable(ctx, cache)
+	Email("b@example.com").CheckDeliverable(ctx, cache)
+	if fake.calls != 1 {
+		t.Errorf("unexpected number of lookups: %d != 1", fake.calls)
+	}
+
+	// Not found answers are cached, temporary failures are not
+	Email("a@missing.example").CheckDeliverable(ctx, cache)
+	Email("a@missing.example").CheckDeliverable(ctx, cache)
+	if fake.calls != 3 {
+		t.Errorf("unexpected number of lookups: %d != 3", fake.calls)
+	}
+	Email("a@slow.example").CheckDeliverable(ctx, cache)
+	Email("a@slow.example").CheckDeliverable(ctx, cache)
+	if fake.calls != 5 {
+		t.Errorf("unexpected number of lookups: %d != 5", fake.calls)
+	}
+
+	// Answers expire after the TTL
+	now = now.Add(time.Minute)
+	Email("a@example.com").CheckDeliverable(ctx, cache)
+	if fake.calls != 6 {
+		t.Errorf("unexpected number of lookups: %d != 6", fake.calls)
+	}
+}
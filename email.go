@@ -6,14 +6,28 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+
+	"github.com/aodin/sol"
+	"github.com/aodin/sol/types"
+	"golang.org/x/net/idna"
 )
 
 // Email is an email type
 type Email string
 
-// Scan converts an SQL value into an Email
+// Scan converts an SQL value into an Email. NULL values will error - use
+// NullEmail for nullable columns.
 func (email *Email) Scan(value interface{}) error {
-	*email = Email(string(value.([]byte)))
+	switch v := value.(type) {
+	case []byte:
+		*email = Email(string(v))
+	case string:
+		*email = Email(v)
+	case nil:
+		return fmt.Errorf("Email scan returned NULL - use NullEmail")
+	default:
+		return fmt.Errorf("Email scan returned unsupported type %T", value)
+	}
 	return nil
 }
 
@@ -22,7 +36,8 @@ func (email Email) Value() (driver.Value, error) {
 	return string(email), nil
 }
 
-// UnmarshalJSON for emails trims spaces
+// UnmarshalJSON for emails normalizes the email and will error if the
+// email is invalid
 func (email *Email) UnmarshalJSON(text []byte) error {
 	b := bytes.NewBuffer(text)
 	dec := json.NewDecoder(b)
@@ -30,14 +45,43 @@ func (email *Email) UnmarshalJSON(text []byte) error {
 	if err := dec.Decode(&n); err != nil {
 		return err
 	}
-	*email = Email(strings.TrimSpace(n))
+	normalized, err := NormalizeEmail(n)
+	if err != nil {
+		return err
+	}
+	*email = Email(normalized)
 	return nil
 }
 
+// ASCII returns the email with its domain in ASCII-compatible (punycode)
+// form. The local part is unchanged. The email is returned as-is if the
+// domain cannot be converted.
+func (email Email) ASCII() string {
+	return email.convertDomain(idna.Lookup.ToASCII)
+}
+
+// Unicode returns the email with its domain in Unicode form. The email is
+// returned as-is if the domain cannot be converted.
+func (email Email) Unicode() string {
+	return email.convertDomain(idna.Lookup.ToUnicode)
+}
+
+func (email Email) convertDomain(convert func(string) (string, error)) string {
+	at := strings.LastIndexByte(string(email), '@')
+	if at == -1 || strings.HasPrefix(string(email[at+1:]), "[") {
+		return string(email)
+	}
+	domain, err := convert(string(email[at+1:]))
+	if err != nil {
+		return string(email)
+	}
+	return string(email[:at+1]) + domain
+}
+
 // Normalize will perform an in-place normalization of the email, only
 // returning an email if normalization fails
-func (email *Email) Normalize() error {
-	normalized, err := NormalizeEmail(string(*email))
+func (email *Email) Normalize(opts ...EmailOption) error {
+	normalized, err := NormalizeEmail(string(*email), opts...)
 	if err != nil {
 		return err
 	}
@@ -45,20 +89,56 @@ func (email *Email) Normalize() error {
 	return nil
 }
 
-// NormalizeEmail will check that the email has at least one @ - it will then
-// lowercase both the local name and domain
-func NormalizeEmail(email string) (string, error) {
-	parts := strings.Split(strings.TrimSpace(email), "@")
-	if len(parts) <= 1 {
-		return "", fmt.Errorf("Emails must contain a '@'")
+// EmailOption configures NormalizeEmail
+type EmailOption func(*emailOptions)
+
+type emailOptions struct {
+	asciiDomain bool
+}
+
+// ASCIIDomain stores internationalized domains in their ASCII-compatible
+// (punycode) form, such as xn--bcher-kva.de
+func ASCIIDomain() EmailOption {
+	return func(opts *emailOptions) {
+		opts.asciiDomain = true
+	}
+}
+
+// UnicodeDomain stores internationalized domains in their Unicode form,
+// such as bücher.de. This is the default.
+func UnicodeDomain() EmailOption {
+	return func(opts *emailOptions) {
+		opts.asciiDomain = false
+	}
+}
+
+// NormalizeEmail will parse the email as an RFC 5322 addr-spec (see
+// ParseAddrSpec) - it will then lowercase both the local name and domain.
+// Domains are processed with IDNA 2008 so that the Unicode and punycode
+// forms of a domain normalize to the same value. Errors are of type
+// EmailError.
+func NormalizeEmail(email string, opts ...EmailOption) (string, error) {
+	var options emailOptions
+	for _, opt := range opts {
+		opt(&options)
 	}
-	if len(parts) > 2 {
-		return "", fmt.Errorf("Emails cannot have more than one '@'")
+	local, domain, err := ParseAddrSpec(strings.TrimSpace(email))
+	if err != nil {
+		return "", err
 	}
-	if parts[0] == "" || parts[1] == "" {
-		return "", fmt.Errorf("Emails must be of the form 'user@domain'")
+	if !strings.HasPrefix(domain, "[") {
+		convert := idna.Lookup.ToUnicode
+		if options.asciiDomain {
+			convert = idna.Lookup.ToASCII
+		}
+		if domain, err = convert(domain); err != nil {
+			return "", emailError(
+				RuleDomain,
+				"Email domain is not a valid internationalized domain: "+err.Error(),
+			)
+		}
 	}
-	return strings.ToLower(strings.Join(parts, "@")), nil
+	return strings.ToLower(local + "@" + domain), nil
 }
 
 // NewEmail creates a new Email
@@ -66,3 +146,84 @@ func NewEmail(email string) Email {
 	out, _ := NormalizeEmail(email)
 	return Email(out)
 }
+
+// EmailColumnElem is a Modifier for an email column. It is created with
+// EmailColumn.
+type EmailColumnElem struct {
+	name    string
+	options columnOptions
+}
+
+var _ sol.Modifier = EmailColumnElem{}
+
+// Type returns the column's type and constraints. Text columns with the
+// Lowercase option CHECK that the email is lowercase.
+func (column EmailColumnElem) Type() types.Type {
+	datatype := columnType{
+		err:     column.validate(),
+		name:    "TEXT",
+		notNull: column.options.notNull,
+		unique:  column.options.unique,
+	}
+	name := quoteIdentifier(column.name)
+	if column.options.citext {
+		datatype.name = "CITEXT"
+	} else if column.options.lowercase {
+		datatype.constraints = append(
+			datatype.constraints,
+			fmt.Sprintf("%s = lower(%s)", name, name),
+		)
+	}
+	if column.options.check {
+		// A non-empty local part, then an '@', then a domain without one
+		datatype.constraints = append(
+			datatype.constraints,
+			fmt.Sprintf("%s ~ '^.+@[^@]+$'", name),
+		)
+	}
+	return datatype
+}
+
+// UniqueLowerIndex returns the statement that creates a unique index on
+// lower() of the column in the given table. Unlike the Lowercase option,
+// writes of mixed case emails are allowed and caught as duplicates. sol
+// does not create indexes, so run the statement after creating the table.
+func (column EmailColumnElem) UniqueLowerIndex(table string) string {
+	return fmt.Sprintf(
+		"CREATE UNIQUE INDEX %s ON %s (lower(%s))",
+		quoteIdentifier(table+"_"+column.name+"_lower_key"),
+		quoteIdentifier(table),
+		quoteIdentifier(column.name),
+	)
+}
+
+// validate errors on unsupported options and on unique columns that would
+// be case-sensitive
+func (column EmailColumnElem) validate() error {
+	if column.options.unique && !column.options.citext && !column.options.lowercase {
+		return fmt.Errorf(
+			"Unique email columns must use CIText or Lowercase - or drop Unique and create a UniqueLowerIndex",
+		)
+	}
+	return column.options.supports(
+		"Email", "NotNull", "Unique", "Check", "Lowercase", "CIText",
+	)
+}
+
+// Modify implements the sol.Modifier interface. It will error if given an
+// unsupported option.
+func (column EmailColumnElem) Modify(table sol.Tabular) error {
+	if err := column.validate(); err != nil {
+		return err
+	}
+	return sol.Column(column.name, column.Type()).Modify(table)
+}
+
+// EmailColumn returns a Modifier for an email column with the given name.
+// It accepts the NotNull, Unique, Check, Lowercase and CIText options.
+// Unique must be combined with CIText or Lowercase, since emails are
+// case-insensitive. For a unique lower() index instead, omit Unique and run
+// the statement of UniqueLowerIndex after the table is created.
+func EmailColumn(name string, opts ...ColumnOption) EmailColumnElem {
+	return EmailColumnElem{name: name, options: newColumnOptions(opts)}
+}
@@ -1,6 +1,10 @@
 package fields
 
-import "testing"
+import (
+	"testing"
+
+	sql "github.com/aodin/sol"
+)
 
 func TestEmail(t *testing.T) {
 	email := NewEmail("A@example.com")
@@ -42,3 +46,98 @@ func TestEmail_Normalize(t *testing.T) {
 		t.Errorf("unexpected email: %s != a@example.com", email)
 	}
 }
+
+func TestEmail_IDNA(t *testing.T) {
+	unicode, err := NormalizeEmail("User@Bücher.DE")
+	if err != nil {
+		t.Fatalf("NormalizeEmail should not error: %s", err)
+	}
+	if unicode != "user@bücher.de" {
+		t.Errorf("unexpected email: %s != user@bücher.de", unicode)
+	}
+
+	// The punycode form should normalize to the same value
+	fromASCII, err := NormalizeEmail("user@xn--bcher-kva.de")
+	if err != nil {
+		t.Fatalf("NormalizeEmail should not error: %s", err)
+	}
+	if fromASCII != unicode {
+		t.Errorf("unexpected email: %s != %s", fromASCII, unicode)
+	}
+
+	ascii, err := NormalizeEmail("user@bücher.de", ASCIIDomain())
+	if err != nil {
+		t.Fatalf("NormalizeEmail should not error: %s", err)
+	}
+	if ascii != "user@xn--bcher-kva.de" {
+		t.Errorf("unexpected email: %s != user@xn--bcher-kva.de", ascii)
+	}
+
+	email := Email(unicode)
+	if email.ASCII() != "user@xn--bcher-kva.de" {
+		t.Errorf("unexpected ASCII email: %s", email.ASCII())
+	}
+	if Email(ascii).Unicode() != "user@bücher.de" {
+		t.Errorf("unexpected Unicode email: %s", Email(ascii).Unicode())
+	}
+
+	// Invalid punycode should error
+	if _, err := NormalizeEmail("user@xn--a.de"); err == nil {
+		t.Errorf("NormalizeEmail should error with invalid punycode")
+	}
+}
+
+var EmailTests = sql.Table("email_tests",
+	Serial{},
+	EmailColumn("email", NotNull(), Unique(), Lowercase(), Check()),
+)
+
+func TestEmailColumn(t *testing.T) {
+	tests := []struct {
+		column EmailColumnElem
+		out    string
+	}{
+		{column: EmailColumn("email"), out: "TEXT"},
+		{
+			column: EmailColumn("email", Unique(), Lowercase()),
+			out:    `TEXT UNIQUE CHECK ("email" = lower("email"))`,
+		},
+		{
+			column: EmailColumn("email", CIText(), Unique()),
+			out:    "CITEXT UNIQUE",
+		},
+		{
+			column: EmailColumn("contact", CIText(), Check()),
+			out:    `CITEXT CHECK ("contact" ~ '^.+@[^@]+$')`,
+		},
+	}
+	for _, test := range tests {
+		out, err := test.column.Type().Create(nil)
+		if err != nil {
+			t.Errorf("Create should not error: %s", err)
+		}
+		if out != test.out {
+			t.Errorf("unexpected column type: %s != %s", out, test.out)
+		}
+	}
+}
+
+func TestEmailColumn_Unsupported(t *testing.T) {
+	for _, opt := range []ColumnOption{PrimaryKey(), Default("x"), Limit(3), Unique()} {
+		column := EmailColumn("email", opt)
+		if err := column.Modify(sql.Table("unsupported")); err == nil {
+			t.Errorf("Modify should error with an unsupported option or a case-sensitive unique column")
+		}
+		if _, err := column.Type().Create(nil); err == nil {
+			t.Errorf("Create should error with an unsupported option")
+		}
+	}
+}
+
+func TestEmailColumn_UniqueLowerIndex(t *testing.T) {
+	out := EmailColumn("email").UniqueLowerIndex("users")
+	expected := `CREATE UNIQUE INDEX "users_email_lower_key" ON "users" (lower("email"))`
+	if out != expected {
+		t.Errorf("unexpected index: %s != %s", out, expected)
+	}
+}
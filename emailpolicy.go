@@ -0,0 +1,167 @@
+package fields
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// PolicyCode identifies why an email was rejected by an EmailPolicy
+type PolicyCode string
+
+// Codes that can be reported by an EmailPolicyError
+const (
+	PolicyDenied     PolicyCode = "denied"
+	PolicyNotAllowed PolicyCode = "not_allowed"
+)
+
+// EmailPolicyError is returned when an email's domain is rejected by an
+// EmailPolicy
+type EmailPolicyError struct {
+	Domain string
+	Code   PolicyCode
+}
+
+// Error implements the error interface
+func (err EmailPolicyError) Error() string {
+	switch err.Code {
+	case PolicyDenied:
+		return fmt.Sprintf("Emails from the domain '%s' are not accepted", err.Domain)
+	case PolicyNotAllowed:
+		return fmt.Sprintf("Emails must be from an allowed domain - '%s' is not allowed", err.Domain)
+	}
+	return fmt.Sprintf("Emails from the domain '%s' are invalid", err.Domain)
+}
+
+// domainSet matches domains exactly, or by subdomain for patterns that
+// begin with "*.", such as *.example.com
+type domainSet struct {
+	exact     map[string]bool
+	wildcards map[string]bool
+}
+
+func (set *domainSet) add(pattern string) {
+	if set.exact == nil {
+		set.exact = make(map[string]bool)
+		set.wildcards = make(map[string]bool)
+	}
+	if strings.HasPrefix(pattern, "*.") {
+		set.wildcards[policyDomain(pattern[2:])] = true
+		return
+	}
+	set.exact[policyDomain(pattern)] = true
+}
+
+func (set domainSet) len() int {
+	return len(set.exact) + len(set.wildcards)
+}
+
+func (set domainSet) matches(domain string) bool {
+	if set.exact[domain] {
+		return true
+	}
+	for i := strings.IndexByte(domain, '.'); i != -1; {
+		domain = domain[i+1:]
+		if set.wildcards[domain] {
+			return true
+		}
+		i = strings.IndexByte(domain, '.')
+	}
+	return false
+}
+
+// policyDomain converts a domain to the form used for matching: lowercase
+// and ASCII-compatible, so that Unicode and punycode domains are equal
+func policyDomain(domain string) string {
+	domain = strings.ToLower(strings.TrimSpace(domain))
+	if ascii, err := idna.Lookup.ToASCII(domain); err == nil {
+		return ascii
+	}
+	return domain
+}
+
+// EmailPolicy restricts the domains that an email can use. Denied domains
+// are always rejected. If any domains are allowed, all other domains are
+// rejected. Domains can be given as wildcards such as *.example.com, which
+// match all subdomains of example.com, but not example.com itself.
+type EmailPolicy struct {
+	allow domainSet
+	deny  domainSet
+}
+
+// Allow adds the given domains to the policy's allowlist
+func (policy *EmailPolicy) Allow(domains ...string) *EmailPolicy {
+	for _, domain := range domains {
+		policy.allow.add(domain)
+	}
+	return policy
+}
+
+// Deny adds the given domains to the policy's denylist
+func (policy *EmailPolicy) Deny(domains ...string) *EmailPolicy {
+	for _, domain := range domains {
+		policy.deny.add(domain)
+	}
+	return policy
+}
+
+// ReadBlocklist adds the domains in the reader to the policy's denylist.
+// The input is plain-text with one domain per line. Blank lines and lines
+// starting with a '#' are ignored.
+func (policy *EmailPolicy) ReadBlocklist(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		policy.deny.add(line)
+	}
+	return scanner.Err()
+}
+
+// LoadBlocklist adds the domains in the file at the given path to the
+// policy's denylist. See ReadBlocklist for the file format.
+func (policy *EmailPolicy) LoadBlocklist(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return policy.ReadBlocklist(f)
+}
+
+// Check returns an EmailPolicyError if the domain is rejected by the policy
+func (policy *EmailPolicy) Check(domain string) error {
+	domain = policyDomain(domain)
+	if policy.deny.matches(domain) {
+		return EmailPolicyError{Domain: domain, Code: PolicyDenied}
+	}
+	if policy.allow.len() > 0 && !policy.allow.matches(domain) {
+		return EmailPolicyError{Domain: domain, Code: PolicyNotAllowed}
+	}
+	return nil
+}
+
+// NewEmailPolicy creates a new EmailPolicy with the given denied domains
+func NewEmailPolicy(deny ...string) *EmailPolicy {
+	return new(EmailPolicy).Deny(deny...)
+}
+
+// Validate returns an EmailError if the email is invalid, or an
+// EmailPolicyError if its domain is rejected by the policy. A nil policy
+// only checks that the email is valid.
+func (email Email) Validate(policy *EmailPolicy) error {
+	_, domain, err := ParseAddrSpec(strings.TrimSpace(string(email)))
+	if err != nil {
+		return err
+	}
+	if policy == nil {
+		return nil
+	}
+	return policy.Check(domain)
+}
@@ -0,0 +1,104 @@
+package fields
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEmailPolicy(t *testing.T) {
+	policy := NewEmailPolicy("mailinator.com", "*.throwaway.io")
+
+	tests := []struct {
+		email string
+		code  PolicyCode
+	}{
+		{email: "a@example.com"},
+		{email: "a@mailinator.com", code: PolicyDenied},
+		{email: "a@MAILINATOR.com", code: PolicyDenied},
+		{email: "a@sub.mailinator.com"},
+		{email: "a@x.throwaway.io", code: PolicyDenied},
+		{email: "a@y.x.throwaway.io", code: PolicyDenied},
+		{email: "a@throwaway.io"},
+	}
+	for _, test := range tests {
+		err := Email(test.email).Validate(policy)
+		if test.code == "" {
+			if err != nil {
+				t.Errorf("%s should be valid: %s", test.email, err)
+			}
+			continue
+		}
+		var policyErr EmailPolicyError
+		if !errors.As(err, &policyErr) {
+			t.Errorf("%s should return an EmailPolicyError", test.email)
+			continue
+		}
+		if policyErr.Code != test.code {
+			t.Errorf("unexpected code for %s: %s != %s", test.email, policyErr.Code, test.code)
+		}
+	}
+
+	// Invalid emails return an EmailError
+	var emailErr EmailError
+	if err := Email("a b@c").Validate(policy); !errors.As(err, &emailErr) {
+		t.Errorf("Validate should return an EmailError for invalid emails")
+	}
+	if err := Email("a@mailinator.com").Validate(nil); err != nil {
+		t.Errorf("Validate with a nil policy should not error: %s", err)
+	}
+}
+
+func TestEmailPolicy_Allow(t *testing.T) {
+	policy := new(EmailPolicy).Allow("corp.example", "*.corp.example", "bücher.de")
+	policy.Deny("legacy.corp.example")
+
+	valid := []string{
+		"a@corp.example", "a@eu.corp.example", "a@xn--bcher-kva.de",
+	}
+	for _, email := range valid {
+		if err := Email(email).Validate(policy); err != nil {
+			t.Errorf("%s should be allowed: %s", email, err)
+		}
+	}
+
+	invalid := []string{"a@example.com", "a@legacy.corp.example"}
+	for _, email := range invalid {
+		if err := Email(email).Validate(policy); err == nil {
+			t.Errorf("%s should not be allowed", email)
+		}
+	}
+}
+
+func TestEmailPolicy_LoadBlocklist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blocklist.txt")
+	contents := strings.Join([]string{
+		"# Disposable domains",
+		"",
+		"10minutemail.com",
+		"  *.guerrillamail.com  ",
+	}, "\n")
+	if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	policy := new(EmailPolicy)
+	if err := policy.LoadBlocklist(path); err != nil {
+		t.Fatalf("LoadBlocklist should not error: %s", err)
+	}
+	if err := policy.Check("10minutemail.com"); err == nil {
+		t.Errorf("10minutemail.com should be denied")
+	}
+	if err := policy.Check("a.guerrillamail.com"); err == nil {
+		t.Errorf("a.guerrillamail.com should be denied")
+	}
+	if err := policy.Check("# Disposable domains"); err != nil {
+		t.Errorf("Comments should be ignored")
+	}
+
+	if err := policy.LoadBlocklist(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Errorf("LoadBlocklist should error with a missing file")
+	}
+}
@@ -0,0 +1,136 @@
+package fields
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/aodin/sol"
+	"github.com/aodin/sol/types"
+)
+
+// Masked returns the email with all but the first character of the local
+// part and of each domain label replaced by asterisks. The top-level
+// domain is kept, for example j***@e******.com. Quoted local parts and
+// domain literals are masked as a whole, such as "****"@[*********].
+func (email Email) Masked() string {
+	at := strings.LastIndexByte(string(email), '@')
+	if at == -1 {
+		return maskPart(string(email))
+	}
+	local, domain := string(email[:at]), string(email[at+1:])
+	if isEnclosed(local, '"', '"') {
+		local = `"` + maskAll(local[1:len(local)-1]) + `"`
+	} else {
+		local = maskPart(local)
+	}
+	if isEnclosed(domain, '[', ']') {
+		return local + "@[" + maskAll(domain[1:len(domain)-1]) + "]"
+	}
+	labels := strings.Split(domain, ".")
+	for i := range labels {
+		if i == len(labels)-1 && i > 0 {
+			break
+		}
+		labels[i] = maskPart(labels[i])
+	}
+	return local + "@" + strings.Join(labels, ".")
+}
+
+func isEnclosed(part string, open, close byte) bool {
+	return len(part) >= 2 && part[0] == open && part[len(part)-1] == close
+}
+
+func maskPart(part string) string {
+	first, size := utf8.DecodeRuneInString(part)
+	if size == 0 {
+		return ""
+	}
+	return string(first) + maskAll(part[size:])
+}
+
+func maskAll(part string) string {
+	return strings.Repeat("*", utf8.RuneCountInString(part))
+}
+
+var _ slog.LogValuer = Email("")
+
+// LogValue implements the slog.LogValuer interface. Emails are logged in
+// their masked form.
+func (email Email) LogValue() slog.Value {
+	return slog.StringValue(email.Masked())
+}
+
+var _ fmt.Formatter = Email("")
+
+// Format implements the fmt.Formatter interface. Emails are masked unless
+// the plus flag is given, as in %+v or %+s. The s, v and q verbs are
+// supported with their usual width, precision and flags. Other verbs are
+// reported as bad verbs with the masked email.
+func (email Email) Format(f fmt.State, verb rune) {
+	out := email.Masked()
+	switch verb {
+	case 's', 'v', 'q':
+		if f.Flag('+') {
+			out = string(email)
+		}
+		fmt.Fprintf(f, fmt.FormatString(f, verb), out)
+	default:
+		fmt.Fprintf(f, "%%!%c(fields.Email=%s)", verb, out)
+	}
+}
+
+// BlindIndex computes keyed HMAC-SHA256 hashes of emails, so that emails
+// can be looked up by equality without storing their plaintext
+type BlindIndex struct {
+	key []byte
+}
+
+// Hash returns the hex encoded HMAC of the normalized email. It will error
+// if the email is invalid or the index was not created by NewBlindIndex.
+func (index BlindIndex) Hash(email Email) (string, error) {
+	if len(index.key) < sha256.Size {
+		return "", fmt.Errorf(
+			"Blind index keys must be at least %d bytes - use NewBlindIndex",
+			sha256.Size,
+		)
+	}
+	normalized, err := NormalizeEmail(string(email))
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, index.key)
+	mac.Write([]byte(normalized))
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// NewBlindIndex creates a new BlindIndex. Keys must be at least 32 bytes.
+func NewBlindIndex(key []byte) (BlindIndex, error) {
+	if len(key) < sha256.Size {
+		return BlindIndex{}, fmt.Errorf(
+			"Blind index keys must be at least %d bytes", sha256.Size,
+		)
+	}
+	return BlindIndex{key: append([]byte(nil), key...)}, nil
+}
+
+// BlindIndexColumn is a Modifier for a unique column that stores the hashes
+// created by a BlindIndex. The string is the column name.
+type BlindIndexColumn string
+
+var _ sol.Modifier = BlindIndexColumn("")
+
+// Modify implements the sol.Modifier interface
+func (name BlindIndexColumn) Modify(table sol.Tabular) error {
+	column := sol.Column(
+		string(name), types.Varchar().Limit(hex.EncodedLen(sha256.Size)).NotNull(),
+	)
+	if err := column.Modify(table); err != nil {
+		return err
+	}
+	return sol.Unique(string(name)).Modify(table)
+}
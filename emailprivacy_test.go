@@ -0,0 +1,101 @@
+package fields
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	sql "github.com/aodin/sol"
+)
+
+var BlindIndexTests = sql.Table("blind_index_tests",
+	Serial{},
+	BlindIndexColumn("email_hash"),
+)
+
+func TestEmail_Masked(t *testing.T) {
+	tests := []struct {
+		in, out string
+	}{
+		{in: "john@example.com", out: "j***@e******.com"},
+		{in: "j@mail.example.co", out: "j@m***.e******.co"},
+		{in: "k@j", out: "k@j"},
+		{in: "jörg@bücher.de", out: "j***@b*****.de"},
+		{in: `"john doe"@example.com`, out: `"********"@e******.com`},
+		{in: `"a"@[192.0.2.1]`, out: `"*"@[*********]`},
+		{in: "a@[IPv6:2001:db8::1]", out: "a@[****************]"},
+		{in: "invalid", out: "i******"},
+		{in: "", out: ""},
+	}
+	for _, test := range tests {
+		if out := Email(test.in).Masked(); out != test.out {
+			t.Errorf("unexpected masked email: %s != %s", out, test.out)
+		}
+	}
+
+	email := Email("john@example.com")
+	if out := fmt.Sprintf("%s %v %q", email, email, email); out != `j***@e******.com j***@e******.com "j***@e******.com"` {
+		t.Errorf("unexpected formatted email: %s", out)
+	}
+	if out := fmt.Sprintf("%+v", email); out != "john@example.com" {
+		t.Errorf("unexpected formatted email: %s", out)
+	}
+
+	// Width, precision and flags are applied to the masked email
+	formats := []struct {
+		format, out string
+	}{
+		{format: "[%-20s]", out: "[j***@e******.com    ]"},
+		{format: "[%20v]", out: "[    j***@e******.com]"},
+		{format: "[%.4s]", out: "[j***]"},
+		{format: "[%+-20s]", out: "[john@example.com    ]"},
+		{format: "[%x]", out: "[%!x(fields.Email=j***@e******.com)]"},
+		{format: "[%+d]", out: "[%!d(fields.Email=j***@e******.com)]"},
+	}
+	for _, test := range formats {
+		if out := fmt.Sprintf(test.format, email); out != test.out {
+			t.Errorf("unexpected %s output: %s != %s", test.format, out, test.out)
+		}
+	}
+
+	var buf bytes.Buffer
+	slog.New(slog.NewTextHandler(&buf, nil)).Info("signup", "email", email)
+	if !strings.Contains(buf.String(), "email=j***@e******.com") {
+		t.Errorf("unexpected log output: %s", buf.String())
+	}
+}
+
+func TestBlindIndex(t *testing.T) {
+	if _, err := NewBlindIndex([]byte("short")); err == nil {
+		t.Errorf("NewBlindIndex should error with a short key")
+	}
+	if _, err := (BlindIndex{}).Hash("a@example.com"); err == nil {
+		t.Errorf("Hash should error with the zero value BlindIndex")
+	}
+
+	index, err := NewBlindIndex(bytes.Repeat([]byte{1}, 32))
+	if err != nil {
+		t.Fatalf("NewBlindIndex should not error: %s", err)
+	}
+	a, err := index.Hash("John@Example.com")
+	if err != nil {
+		t.Fatalf("Hash should not error: %s", err)
+	}
+	if len(a) != 64 {
+		t.Errorf("unexpected hash length: %d != 64", len(a))
+	}
+	b, _ := index.Hash("john@example.com")
+	if a != b {
+		t.Errorf("Hashes of equivalent emails should be equal")
+	}
+
+	other, _ := NewBlindIndex(bytes.Repeat([]byte{2}, 32))
+	if c, _ := other.Hash("john@example.com"); a == c {
+		t.Errorf("Hashes with different keys should not be equal")
+	}
+	if _, err := index.Hash("invalid"); err == nil {
+		t.Errorf("Hash should error with an invalid email")
+	}
+}
@@ -0,0 +1,150 @@
+package fields
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aodin/sol"
+	"github.com/aodin/sol/types"
+	"github.com/lib/pq"
+)
+
+// EmailsError reports the errors of the invalid elements of an email list
+// by their index
+type EmailsError map[int]error
+
+// Error implements the error interface
+func (errs EmailsError) Error() string {
+	indexes := make([]int, 0, len(errs))
+	for i := range errs {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	messages := make([]string, len(indexes))
+	for i, index := range indexes {
+		messages[i] = fmt.Sprintf("email %d: %s", index, errs[index])
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Emails is a list of emails stored as a Postgres text[] array
+type Emails []Email
+
+// Strings returns the emails as a slice of strings
+func (emails Emails) Strings() []string {
+	out := make([]string, len(emails))
+	for i, email := range emails {
+		out[i] = string(email)
+	}
+	return out
+}
+
+// Scan converts an SQL array into Emails
+func (emails *Emails) Scan(value interface{}) error {
+	var array pq.StringArray
+	if err := array.Scan(value); err != nil {
+		return err
+	}
+	if array == nil {
+		*emails = nil
+		return nil
+	}
+	out := make(Emails, len(array))
+	for i, email := range array {
+		out[i] = Email(email)
+	}
+	*emails = out
+	return nil
+}
+
+// Value returns the emails formatted as an SQL array
+func (emails Emails) Value() (driver.Value, error) {
+	if emails == nil {
+		return nil, nil
+	}
+	return pq.StringArray(emails.Strings()).Value()
+}
+
+// UnmarshalJSON normalizes and deduplicates the emails. If any emails are
+// invalid, an EmailsError is returned. JSON null sets the emails to nil,
+// which is NULL in SQL.
+func (emails *Emails) UnmarshalJSON(text []byte) error {
+	b := bytes.NewBuffer(text)
+	dec := json.NewDecoder(b)
+	var list []string
+	if err := dec.Decode(&list); err != nil {
+		return err
+	}
+	if list == nil {
+		*emails = nil
+		return nil
+	}
+	parsed, err := ParseEmails(list...)
+	if err != nil {
+		return err
+	}
+	*emails = parsed
+	return nil
+}
+
+// ParseEmails normalizes each email with NormalizeEmail and removes
+// duplicates, keeping the first occurrence. If any emails are invalid, an
+// EmailsError is returned.
+func ParseEmails(list ...string) (Emails, error) {
+	errs := make(EmailsError)
+	seen := make(map[string]bool)
+	emails := make(Emails, 0, len(list))
+	for i, email := range list {
+		normalized, err := NormalizeEmail(email)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		if seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
+		emails = append(emails, Email(normalized))
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return emails, nil
+}
+
+// EmailsColumnElem is a Modifier for a text[] column of emails. It is
+// created with EmailsColumn.
+type EmailsColumnElem struct {
+	name    string
+	options columnOptions
+}
+
+var _ sol.Modifier = EmailsColumnElem{}
+
+// Type returns the column's type and constraints
+func (column EmailsColumnElem) Type() types.Type {
+	return columnType{
+		err:     column.options.supports("Emails", "NotNull"),
+		name:    "TEXT[]",
+		notNull: column.options.notNull,
+	}
+}
+
+// Modify implements the sol.Modifier interface. It will error if given an
+// unsupported option.
+func (column EmailsColumnElem) Modify(table sol.Tabular) error {
+	if err := column.options.supports("Emails", "NotNull"); err != nil {
+		return err
+	}
+	return sol.Column(column.name, column.Type()).Modify(table)
+}
+
+// EmailsColumn returns a Modifier for a text[] column of emails with the
+// given name. It accepts only the NotNull option.
+func EmailsColumn(name string, opts ...ColumnOption) EmailsColumnElem {
+	return EmailsColumnElem{name: name, options: newColumnOptions(opts)}
+}
@@ -0,0 +1,93 @@
+package fields
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	sql "github.com/aodin/sol"
+)
+
+var EmailsTests = sql.Table("emails_tests",
+	Serial{},
+	EmailsColumn("cc", NotNull()),
+)
+
+func TestEmails(t *testing.T) {
+	var emails Emails
+	if err := json.Unmarshal(
+		[]byte(`["A@example.com", "b@example.com", "a@EXAMPLE.com"]`), &emails,
+	); err != nil {
+		t.Fatalf("Unmarshal JSON should not error: %s", err)
+	}
+	if len(emails) != 2 || emails[0] != "a@example.com" || emails[1] != "b@example.com" {
+		t.Errorf("unexpected emails: %v", emails.Strings())
+	}
+
+	err := json.Unmarshal([]byte(`["a@example.com", "invalid", "c@", "d@example.com"]`), &emails)
+	var emailsErr EmailsError
+	if !errors.As(err, &emailsErr) {
+		t.Fatalf("Unmarshal JSON should return an EmailsError: %v", err)
+	}
+	if len(emailsErr) != 2 || emailsErr[1] == nil || emailsErr[2] == nil {
+		t.Errorf("unexpected errors: %s", emailsErr)
+	}
+	if len(emails) != 2 {
+		t.Errorf("emails should be unchanged after an error")
+	}
+
+	// JSON null is SQL NULL, while an empty array is not
+	test := struct {
+		CC Emails `json:"cc"`
+	}{CC: emails}
+	if err := json.Unmarshal([]byte(`{"cc":null}`), &test); err != nil {
+		t.Fatalf("Unmarshal JSON should not error with null: %s", err)
+	}
+	if test.CC != nil {
+		t.Errorf("emails should be nil after null: %v", test.CC)
+	}
+	if value, _ := test.CC.Value(); value != nil {
+		t.Errorf("Value should return nil after null: %v", value)
+	}
+	if err := json.Unmarshal([]byte(`{"cc":[]}`), &test); err != nil {
+		t.Fatalf("Unmarshal JSON should not error with an empty array: %s", err)
+	}
+	if test.CC == nil || len(test.CC) != 0 {
+		t.Errorf("emails should be empty but not nil: %#v", test.CC)
+	}
+}
+
+func TestEmails_Scan(t *testing.T) {
+	var emails Emails
+	if err := emails.Scan([]byte(`{a@example.com,b@example.com}`)); err != nil {
+		t.Fatalf("Scan should not error: %s", err)
+	}
+	if len(emails) != 2 || emails[1] != "b@example.com" {
+		t.Errorf("unexpected emails: %v", emails.Strings())
+	}
+
+	if err := emails.Scan(nil); err != nil {
+		t.Fatalf("Scan should not error with NULL: %s", err)
+	}
+	if emails != nil {
+		t.Errorf("emails should be nil after scanning NULL")
+	}
+	if value, _ := emails.Value(); value != nil {
+		t.Errorf("Value should return nil for nil emails")
+	}
+}
+
+func TestEmailsColumn(t *testing.T) {
+	out, err := EmailsColumn("cc", NotNull()).Type().Create(nil)
+	if err != nil {
+		t.Errorf("Create should not error: %s", err)
+	}
+	if out != "TEXT[] NOT NULL" {
+		t.Errorf("unexpected column type: %s != TEXT[] NOT NULL", out)
+	}
+	for _, opt := range []ColumnOption{Unique(), Check()} {
+		if err := EmailsColumn("cc", opt).Modify(sql.Table("unsupported")); err == nil {
+			t.Errorf("Modify should error with an unsupported option")
+		}
+	}
+}
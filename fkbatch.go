@@ -0,0 +1,99 @@
+package fields
+
+import (
+	"context"
+
+	"github.com/aodin/sol"
+)
+
+// groupFKs groups the unique IDs of the foreign keys by table, in the order
+// the tables are first seen. Keys without a table are skipped.
+func groupFKs(fks []ImmutableFK) (tables []*sol.TableElem, ids map[*sol.TableElem][]uint64) {
+	ids = make(map[*sol.TableElem][]uint64)
+	seen := make(map[*sol.TableElem]map[uint64]bool)
+	for _, fk := range fks {
+		if fk.Table == nil {
+			continue
+		}
+		if seen[fk.Table] == nil {
+			seen[fk.Table] = make(map[uint64]bool)
+			tables = append(tables, fk.Table)
+		}
+		if !seen[fk.Table][fk.ID] {
+			seen[fk.Table][fk.ID] = true
+			ids[fk.Table] = append(ids[fk.Table], fk.ID)
+		}
+	}
+	return
+}
+
+// ExistsAll checks that every foreign key table has an entry with the
+// key's ID, using one query per table. It returns the keys that do not
+// exist, in the order given. As with Exists, keys without a table do not
+// exist.
+func ExistsAll(conn sol.Conn, fks ...ImmutableFK) ([]ImmutableFK, error) {
+	tables, ids := groupFKs(fks)
+	found := make(map[*sol.TableElem]map[uint64]bool)
+	for _, table := range tables {
+		var existing []int64
+		stmt := sol.Select(
+			table.C("id"),
+		).Where(
+			table.C("id").In(ids[table]),
+		)
+		if err := conn.Query(stmt, &existing); err != nil {
+			return nil, err
+		}
+		found[table] = make(map[uint64]bool)
+		for _, id := range existing {
+			found[table][uint64(id)] = true
+		}
+	}
+
+	var missing []ImmutableFK
+	for _, fk := range fks {
+		if !found[fk.Table][fk.ID] {
+			missing = append(missing, fk)
+		}
+	}
+	return missing, nil
+}
+
+// Prefetch loads the rows of the typed foreign keys using one query per
+// table and caches them, so later calls to Load do not query. Keys that are
+// already cached are skipped. Keys without a matching row are left
+// unloaded - use ExistsAll to find them.
+func Prefetch[T interface{ GetID() uint64 }](ctx context.Context, conn sol.Conn, fks ...*FK[T]) error {
+	var unloaded []ImmutableFK
+	for _, fk := range fks {
+		if _, ok := fk.Cached(); !ok {
+			unloaded = append(unloaded, fk.ImmutableFK)
+		}
+	}
+	tables, ids := groupFKs(unloaded)
+
+	loaded := make(map[*sol.TableElem]map[uint64]T)
+	for _, table := range tables {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var rows []T
+		stmt := table.Select().Where(
+			table.C("id").In(ids[table]),
+		)
+		if err := conn.Query(stmt, &rows); err != nil {
+			return err
+		}
+		loaded[table] = make(map[uint64]T, len(rows))
+		for _, row := range rows {
+			loaded[table][row.GetID()] = row
+		}
+	}
+
+	for _, fk := range fks {
+		if row, ok := loaded[fk.Table][fk.ID]; ok {
+			fk.Cache(row)
+		}
+	}
+	return nil
+}
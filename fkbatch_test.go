@@ -0,0 +1,89 @@
+package fields
+
+import (
+	"context"
+	"testing"
+
+	sql "github.com/aodin/sol"
+)
+
+var FKGroups = sql.Table("fk_groups", Serial{})
+
+func TestExistsAll(t *testing.T) {
+	// Each query returns the existing IDs of the next table
+	results := [][]int64{{1, 3}, {5}}
+	conn := &fakeConn{}
+	conn.query = func(dest ...interface{}) error {
+		*(dest[0].(*[]int64)) = results[conn.queries-1]
+		return nil
+	}
+
+	fks := []ImmutableFK{
+		{ID: 1, Table: FKUsers},
+		{ID: 2, Table: FKUsers},
+		{ID: 5, Table: FKGroups},
+		{ID: 3, Table: FKUsers},
+		{ID: 2, Table: FKUsers},
+		{ID: 6, Table: FKGroups},
+		{ID: 1},
+	}
+	missing, err := ExistsAll(conn, fks...)
+	if err != nil {
+		t.Fatalf("ExistsAll should not error: %s", err)
+	}
+	if conn.queries != 2 {
+		t.Errorf("ExistsAll should query once per table: %d queries", conn.queries)
+	}
+	expected := []ImmutableFK{fks[1], fks[4], fks[5], fks[6]}
+	if len(missing) != len(expected) {
+		t.Fatalf("unexpected missing keys: %v", missing)
+	}
+	for i, fk := range expected {
+		if missing[i] != fk {
+			t.Errorf("unexpected missing key: %v != %v", missing[i], fk)
+		}
+	}
+}
+
+func TestPrefetch(t *testing.T) {
+	conn := &fakeConn{query: func(dest ...interface{}) error {
+		*(dest[0].(*[]fkUser)) = []fkUser{
+			{ID: 1, Name: "admin"},
+			{ID: 2, Name: "client"},
+		}
+		return nil
+	}}
+
+	fks := []*FK[fkUser]{
+		{ImmutableFK: ImmutableFK{ID: 1, Table: FKUsers}},
+		{ImmutableFK: ImmutableFK{ID: 2, Table: FKUsers}},
+		{ImmutableFK: ImmutableFK{ID: 1, Table: FKUsers}},
+		{ImmutableFK: ImmutableFK{ID: 4, Table: FKUsers}},
+	}
+	if err := Prefetch(context.Background(), conn, fks...); err != nil {
+		t.Fatalf("Prefetch should not error: %s", err)
+	}
+	if conn.queries != 1 {
+		t.Errorf("Prefetch should query once per table: %d queries", conn.queries)
+	}
+	for i, name := range []string{"admin", "client", "admin"} {
+		user, ok := fks[i].Cached()
+		if !ok || user.Name != name {
+			t.Errorf("unexpected prefetched user: %+v", user)
+		}
+	}
+	if _, ok := fks[3].Cached(); ok {
+		t.Errorf("keys without a row should not be cached")
+	}
+
+	// Loaded keys are not queried again
+	if _, err := fks[0].Load(context.Background(), conn); err != nil {
+		t.Errorf("Load should not error: %s", err)
+	}
+	if err := Prefetch(context.Background(), conn, fks[:3]...); err != nil {
+		t.Errorf("Prefetch should not error: %s", err)
+	}
+	if conn.queries != 1 {
+		t.Errorf("Prefetch should use the cache: %d queries", conn.queries)
+	}
+}
@@ -0,0 +1,139 @@
+package fields
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/mail"
+	"strings"
+	"unicode/utf8"
+)
+
+// Mailbox is an email with an optional display name, such as
+// "Jane Doe" <jane@example.com>
+type Mailbox struct {
+	Name    string `json:"name"`
+	Address Email  `json:"address"`
+}
+
+// String returns the mailbox formatted for an outgoing header. Names are
+// quoted, or encoded as an RFC 2047 encoded word if they are not ASCII.
+// Domains are in their ASCII form, so only non-ASCII local parts require
+// SMTPUTF8.
+func (mailbox Mailbox) String() string {
+	if mailbox.Name == "" {
+		return mailbox.Address.ASCII()
+	}
+	return quoteDisplayName(mailbox.Name) + " <" + mailbox.Address.ASCII() + ">"
+}
+
+func quoteDisplayName(name string) string {
+	for i := 0; i < len(name); i++ {
+		if name[i] >= utf8.RuneSelf || name[i] < 32 || name[i] == 127 {
+			return mime.QEncoding.Encode("utf-8", name)
+		}
+	}
+	var out strings.Builder
+	out.WriteByte('"')
+	for i := 0; i < len(name); i++ {
+		if name[i] == '"' || name[i] == '\\' {
+			out.WriteByte('\\')
+		}
+		out.WriteByte(name[i])
+	}
+	out.WriteByte('"')
+	return out.String()
+}
+
+// Scan converts an SQL value into a Mailbox
+func (mailbox *Mailbox) Scan(value interface{}) error {
+	var text string
+	switch v := value.(type) {
+	case []byte:
+		text = string(v)
+	case string:
+		text = v
+	default:
+		return fmt.Errorf("Mailbox scan returned unsupported type %T", value)
+	}
+	parsed, err := ParseMailbox(text)
+	if err != nil {
+		return err
+	}
+	*mailbox = parsed
+	return nil
+}
+
+// Value returns the mailbox as a string
+func (mailbox Mailbox) Value() (driver.Value, error) {
+	return mailbox.String(), nil
+}
+
+// UnmarshalJSON accepts either an object with name and address keys or a
+// mailbox string such as "Jane Doe <jane@example.com>"
+func (mailbox *Mailbox) UnmarshalJSON(text []byte) error {
+	if len(bytes.TrimSpace(text)) > 0 && bytes.TrimSpace(text)[0] == '"' {
+		var s string
+		if err := json.Unmarshal(text, &s); err != nil {
+			return err
+		}
+		parsed, err := ParseMailbox(s)
+		if err != nil {
+			return err
+		}
+		*mailbox = parsed
+		return nil
+	}
+	// The alias prevents recursion
+	type alias Mailbox
+	var parsed alias
+	if err := json.Unmarshal(text, &parsed); err != nil {
+		return err
+	}
+	*mailbox = Mailbox(parsed)
+	return nil
+}
+
+// ParseMailbox parses a single mailbox, such as "Jane Doe <jane@example.com>"
+func ParseMailbox(text string) (Mailbox, error) {
+	mailboxes, err := ParseMailboxList(text)
+	if err != nil {
+		return Mailbox{}, err
+	}
+	if len(mailboxes) != 1 {
+		return Mailbox{}, fmt.Errorf(
+			"Expected a single mailbox, but found %d", len(mailboxes),
+		)
+	}
+	return mailboxes[0], nil
+}
+
+// ParseMailboxList parses a comma separated list of mailboxes, as found in
+// a To or Cc header. Display names can be quoted or RFC 2047 encoded words.
+// Members of groups, such as "Team: a@example.com, b@example.com;", are
+// included in the list, but the group names are not. Each address is
+// normalized with NormalizeEmail.
+func ParseMailboxList(list string) ([]Mailbox, error) {
+	if strings.TrimSpace(list) == "" {
+		return nil, nil
+	}
+	addresses, err := mail.ParseAddressList(list)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse mailbox list: %w", err)
+	}
+	mailboxes := make([]Mailbox, len(addresses))
+	for i, address := range addresses {
+		// net/mail removes the quotes from local parts
+		at := strings.LastIndexByte(address.Address, '@')
+		normalized, err := NormalizeEmail(
+			quoteLocalPart(address.Address[:at]) + address.Address[at:],
+		)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mailbox %d: %w", i, err)
+		}
+		mailboxes[i] = Mailbox{Name: address.Name, Address: Email(normalized)}
+	}
+	return mailboxes, nil
+}
@@ -0,0 +1,107 @@
+package fields
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseMailboxList(t *testing.T) {
+	mailboxes, err := ParseMailboxList(
+		`"Jane Doe" <Jane@Example.com>, bob@x.org, ` +
+			`Team: "John" <"john@work"@example.com>, =?UTF-8?Q?J=C3=B6rg?= <jorg@Bücher.de>;, ` +
+			`undisclosed-recipients:;`,
+	)
+	if err != nil {
+		t.Fatalf("ParseMailboxList should not error: %s", err)
+	}
+	expected := []Mailbox{
+		{Name: "Jane Doe", Address: "jane@example.com"},
+		{Address: "bob@x.org"},
+		{Name: "John", Address: `"john@work"@example.com`},
+		{Name: "Jörg", Address: "jorg@bücher.de"},
+	}
+	if len(mailboxes) != len(expected) {
+		t.Fatalf("unexpected number of mailboxes: %d != %d", len(mailboxes), len(expected))
+	}
+	for i, mailbox := range mailboxes {
+		if mailbox != expected[i] {
+			t.Errorf("unexpected mailbox %d: %+v != %+v", i, mailbox, expected[i])
+		}
+	}
+
+	if _, err := ParseMailboxList("a@b, c"); err == nil {
+		t.Errorf("ParseMailboxList should error with a missing address")
+	}
+	if _, err := ParseMailboxList("a@b, c@d_e.com"); err == nil {
+		t.Errorf("ParseMailboxList should error with an invalid domain")
+	}
+	if _, err := ParseMailbox("a@b, c@d"); err == nil {
+		t.Errorf("ParseMailbox should error with more than one mailbox")
+	}
+}
+
+func TestMailbox(t *testing.T) {
+	tests := []struct {
+		mailbox Mailbox
+		out     string
+	}{
+		{mailbox: Mailbox{Address: "a@example.com"}, out: "a@example.com"},
+		{
+			mailbox: Mailbox{Name: "Jane Doe", Address: "jane@example.com"},
+			out:     `"Jane Doe" <jane@example.com>`,
+		},
+		{
+			mailbox: Mailbox{Name: `Jane "JD" Doe`, Address: "jane@example.com"},
+			out:     `"Jane \"JD\" Doe" <jane@example.com>`,
+		},
+		{
+			mailbox: Mailbox{Name: "Jörg", Address: "jorg@example.com"},
+			out:     "=?utf-8?q?J=C3=B6rg?= <jorg@example.com>",
+		},
+		{
+			mailbox: Mailbox{Name: "x", Address: "a@bücher.de"},
+			out:     `"x" <a@xn--bcher-kva.de>`,
+		},
+		{
+			mailbox: Mailbox{Address: "jörg@bücher.de"},
+			out:     "jörg@xn--bcher-kva.de",
+		},
+	}
+	for _, test := range tests {
+		if test.mailbox.String() != test.out {
+			t.Errorf("unexpected mailbox string: %s != %s", test.mailbox, test.out)
+		}
+
+		// The string form should round trip through the database
+		var scanned Mailbox
+		value, _ := test.mailbox.Value()
+		if err := scanned.Scan(value); err != nil {
+			t.Errorf("Scan should not error: %s", err)
+		}
+		if scanned != test.mailbox {
+			t.Errorf("unexpected scanned mailbox: %+v != %+v", scanned, test.mailbox)
+		}
+	}
+
+	b, err := json.Marshal(Mailbox{Name: "Jane", Address: "jane@example.com"})
+	if err != nil {
+		t.Fatalf("Marshal JSON should not error: %s", err)
+	}
+	if string(b) != `{"name":"Jane","address":"jane@example.com"}` {
+		t.Errorf("unexpected JSON: %s", b)
+	}
+
+	var mailbox Mailbox
+	if err := json.Unmarshal(b, &mailbox); err != nil {
+		t.Errorf("Unmarshal JSON should not error: %s", err)
+	}
+	if err := json.Unmarshal([]byte(`"Bob <BOB@x.org>"`), &mailbox); err != nil {
+		t.Errorf("Unmarshal JSON should not error: %s", err)
+	}
+	if mailbox.Name != "Bob" || mailbox.Address != "bob@x.org" {
+		t.Errorf("unexpected mailbox: %+v", mailbox)
+	}
+	if err := json.Unmarshal([]byte(`{"address":"invalid"}`), &mailbox); err == nil {
+		t.Errorf("Unmarshal JSON should error with an invalid address")
+	}
+}
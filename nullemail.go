@@ -0,0 +1,62 @@
+package fields
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+)
+
+// NullEmail is an Email that can be NULL. It embeds an Email
+type NullEmail struct {
+	Email
+	Valid bool
+}
+
+// Scan converts the raw SQL value into a NullEmail
+func (email *NullEmail) Scan(value interface{}) error {
+	if value == nil {
+		email.Email, email.Valid = "", false
+		return nil
+	}
+	if err := email.Email.Scan(value); err != nil {
+		return err
+	}
+	email.Valid = true
+	return nil
+}
+
+// Value returns the email or nil if the email is not valid
+func (email NullEmail) Value() (driver.Value, error) {
+	if !email.Valid {
+		return nil, nil
+	}
+	return email.Email.Value()
+}
+
+// MarshalJSON returns the email as a JSON string or null
+func (email NullEmail) MarshalJSON() ([]byte, error) {
+	if !email.Valid {
+		return []byte(`null`), nil
+	}
+	return json.Marshal(string(email.Email))
+}
+
+// UnmarshalJSON normalizes the email. Both null and an empty string will
+// set the email as not valid.
+func (email *NullEmail) UnmarshalJSON(text []byte) error {
+	if string(text) == "null" || string(text) == `""` {
+		email.Email, email.Valid = "", false
+		return nil
+	}
+	if err := email.Email.UnmarshalJSON(text); err != nil {
+		return err
+	}
+	email.Valid = true
+	return nil
+}
+
+// NewNullEmail creates a new NullEmail. It is only valid if the email
+// could be normalized.
+func NewNullEmail(email string) NullEmail {
+	out := NewEmail(email)
+	return NullEmail{Email: out, Valid: out != ""}
+}
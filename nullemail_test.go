@@ -0,0 +1,86 @@
+package fields
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNullEmail(t *testing.T) {
+	test := struct {
+		Email NullEmail `json:"email"`
+	}{}
+
+	if err := json.Unmarshal([]byte(`{"email":"A@Example.com"}`), &test); err != nil {
+		t.Fatalf("Unmarshal JSON should not error: %s", err)
+	}
+	if !test.Email.Valid {
+		t.Errorf("test.Email should be valid")
+	}
+	if test.Email.Email != "a@example.com" {
+		t.Errorf("unexpected test.Email: %s != a@example.com", test.Email.Email)
+	}
+
+	if err := json.Unmarshal([]byte(`{"email":"invalid"}`), &test); err == nil {
+		t.Errorf("Unmarshal JSON should error with an invalid email")
+	}
+
+	// Nullable emails can always be nullified
+	if err := json.Unmarshal([]byte(`{"email":null}`), &test); err != nil {
+		t.Errorf("Unmarshal JSON should not error when given null")
+	}
+	if test.Email.Valid {
+		t.Errorf("test.Email should not be valid")
+	}
+
+	b, err := json.Marshal(test)
+	if err != nil {
+		t.Fatalf("Marshal JSON should not error: %s", err)
+	}
+	if string(b) != `{"email":null}` {
+		t.Errorf(`unexpected JSON: %s != {"email":null}`, b)
+	}
+	test.Email = NewNullEmail("a@example.com")
+	if b, _ = json.Marshal(test); string(b) != `{"email":"a@example.com"}` {
+		t.Errorf(`unexpected JSON: %s != {"email":"a@example.com"}`, b)
+	}
+
+	// Invalid emails are NULL
+	invalid := NewNullEmail("invalid")
+	if invalid.Valid {
+		t.Errorf("NewNullEmail should not be valid with an invalid email")
+	}
+	if value, _ := invalid.Value(); value != nil {
+		t.Errorf("Value should return nil for an invalid email: %v", value)
+	}
+}
+
+func TestNullEmail_Scan(t *testing.T) {
+	var email NullEmail
+	for _, value := range []interface{}{"a@example.com", []byte("a@example.com")} {
+		if err := email.Scan(value); err != nil {
+			t.Errorf("Scan should not error: %s", err)
+		}
+		if !email.Valid || email.Email != "a@example.com" {
+			t.Errorf("unexpected scanned email: %+v", email)
+		}
+	}
+
+	if err := email.Scan(nil); err != nil {
+		t.Errorf("Scan should not error with NULL: %s", err)
+	}
+	if email.Valid {
+		t.Errorf("email should not be valid")
+	}
+	if value, _ := email.Value(); value != nil {
+		t.Errorf("Value should return nil for an invalid email")
+	}
+
+	// Emails cannot scan NULL or unsupported types
+	var notNull Email
+	if err := notNull.Scan(nil); err == nil {
+		t.Errorf("Email.Scan should error with NULL")
+	}
+	if err := notNull.Scan(1); err == nil {
+		t.Errorf("Email.Scan should error with an unsupported type")
+	}
+}
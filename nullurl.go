@@ -0,0 +1,55 @@
+package fields
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+)
+
+// NullURL is a URL that can be NULL. It embeds a URL
+type NullURL struct {
+	URL
+	Valid bool
+}
+
+// Scan converts the raw SQL value into a NullURL
+func (url *NullURL) Scan(value interface{}) error {
+	if value == nil {
+		url.URL, url.Valid = "", false
+		return nil
+	}
+	if err := url.URL.Scan(value); err != nil {
+		return err
+	}
+	url.Valid = true
+	return nil
+}
+
+// Value returns the URL or nil if the URL is not valid
+func (url NullURL) Value() (driver.Value, error) {
+	if !url.Valid {
+		return nil, nil
+	}
+	return url.URL.Value()
+}
+
+// MarshalJSON returns the URL as a JSON string or null
+func (url NullURL) MarshalJSON() ([]byte, error) {
+	if !url.Valid {
+		return []byte(`null`), nil
+	}
+	return json.Marshal(string(url.URL))
+}
+
+// UnmarshalJSON validates the URL. Both null and an empty string will set
+// the URL as not valid.
+func (url *NullURL) UnmarshalJSON(text []byte) error {
+	if string(text) == "null" || string(text) == `""` {
+		url.URL, url.Valid = "", false
+		return nil
+	}
+	if err := url.URL.UnmarshalJSON(text); err != nil {
+		return err
+	}
+	url.Valid = true
+	return nil
+}
@@ -0,0 +1,50 @@
+package fields
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNullURL(t *testing.T) {
+	test := struct {
+		Homepage NullURL `json:"homepage"`
+	}{}
+
+	if err := json.Unmarshal([]byte(`{"homepage":"https://example.com"}`), &test); err != nil {
+		t.Fatalf("Unmarshal JSON should not error: %s", err)
+	}
+	if !test.Homepage.Valid || test.Homepage.URL != "https://example.com" {
+		t.Errorf("unexpected test.Homepage: %+v", test.Homepage)
+	}
+	if err := json.Unmarshal([]byte(`{"homepage":"%zz"}`), &test); err == nil {
+		t.Errorf("Unmarshal JSON should error with an invalid URL")
+	}
+
+	// Nullable URLs can always be nullified
+	if err := json.Unmarshal([]byte(`{"homepage":null}`), &test); err != nil {
+		t.Errorf("Unmarshal JSON should not error when given null")
+	}
+	if test.Homepage.Valid {
+		t.Errorf("test.Homepage should not be valid")
+	}
+	if b, _ := json.Marshal(test); string(b) != `{"homepage":null}` {
+		t.Errorf(`unexpected JSON: %s != {"homepage":null}`, b)
+	}
+
+	var url NullURL
+	if err := url.Scan(nil); err != nil {
+		t.Errorf("Scan should not error with NULL: %s", err)
+	}
+	if value, _ := url.Value(); value != nil {
+		t.Errorf("Value should return nil for an invalid URL")
+	}
+	if err := url.Scan([]byte("https://example.com")); err != nil {
+		t.Errorf("Scan should not error: %s", err)
+	}
+	if !url.Valid {
+		t.Errorf("url should be valid")
+	}
+	if b, _ := json.Marshal(url); string(b) != `"https://example.com"` {
+		t.Errorf(`unexpected JSON: %s != "https://example.com"`, b)
+	}
+}
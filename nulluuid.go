@@ -0,0 +1,110 @@
+package fields
+
+import (
+	"database/sql/driver"
+
+	"github.com/aodin/sol"
+	"github.com/aodin/sol/postgres"
+)
+
+// NullUUID is a UUID that can be NULL. It embeds a UUID, but shadows its
+// encoding methods so that the UUID is valid only when it is set.
+type NullUUID struct {
+	UUID
+	Valid bool
+}
+
+var _ sol.Modifier = NullUUID{}
+
+// Scan converts the raw SQL value into a NullUUID
+func (uuid *NullUUID) Scan(value interface{}) error {
+	if value == nil {
+		uuid.UUID, uuid.Valid = UUID{}, false
+		return nil
+	}
+	if err := uuid.UUID.Scan(value); err != nil {
+		return err
+	}
+	uuid.Valid = true
+	return nil
+}
+
+// Value returns the UUID or nil if the UUID is not valid
+func (uuid NullUUID) Value() (driver.Value, error) {
+	if !uuid.Valid {
+		return nil, nil
+	}
+	return uuid.UUID.Value()
+}
+
+// MarshalJSON returns the UUID as a JSON string or null
+func (uuid NullUUID) MarshalJSON() ([]byte, error) {
+	if !uuid.Valid {
+		return []byte(`null`), nil
+	}
+	return uuid.UUID.MarshalJSON()
+}
+
+// UnmarshalJSON parses the UUID. Both null and an empty string will set the
+// UUID as not valid.
+func (uuid *NullUUID) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" || string(data) == `""` {
+		uuid.UUID, uuid.Valid = UUID{}, false
+		return nil
+	}
+	if err := uuid.UUID.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	uuid.Valid = true
+	return nil
+}
+
+// MarshalText returns the UUID or empty text if the UUID is not valid
+func (uuid NullUUID) MarshalText() ([]byte, error) {
+	if !uuid.Valid {
+		return []byte{}, nil
+	}
+	return uuid.UUID.MarshalText()
+}
+
+// UnmarshalText parses the UUID. Empty text will set the UUID as not valid.
+func (uuid *NullUUID) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		uuid.UUID, uuid.Valid = UUID{}, false
+		return nil
+	}
+	if err := uuid.UUID.UnmarshalText(text); err != nil {
+		return err
+	}
+	uuid.Valid = true
+	return nil
+}
+
+// MarshalBinary returns the 16 bytes of the UUID or no bytes if the UUID
+// is not valid
+func (uuid NullUUID) MarshalBinary() ([]byte, error) {
+	if !uuid.Valid {
+		return []byte{}, nil
+	}
+	return uuid.UUID.MarshalBinary()
+}
+
+// UnmarshalBinary requires exactly 16 bytes, or no bytes to set the UUID
+// as not valid
+func (uuid *NullUUID) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		uuid.UUID, uuid.Valid = UUID{}, false
+		return nil
+	}
+	if err := uuid.UUID.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	uuid.Valid = true
+	return nil
+}
+
+// Modify implements the sol.Modifier interface. Unlike UUID, the column
+// can be NULL.
+func (uuid NullUUID) Modify(table sol.Tabular) error {
+	return sol.Column("uuid", postgres.UUID()).Modify(table)
+}
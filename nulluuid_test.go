@@ -0,0 +1,118 @@
+package fields
+
+import (
+	"encoding"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+
+	sql "github.com/aodin/sol"
+)
+
+var NullUUIDTests = sql.Table("null_uuid_tests",
+	Serial{},
+	NullUUID{},
+)
+
+func TestNullUUID(t *testing.T) {
+	test := struct {
+		UUID NullUUID `json:"uuid"`
+	}{}
+
+	id := NewUUID()
+	b, _ := json.Marshal(map[string]UUID{"uuid": id})
+	if err := json.Unmarshal(b, &test); err != nil {
+		t.Fatalf("Unmarshal JSON should not error: %s", err)
+	}
+	if !test.UUID.Valid || test.UUID.UUID != id {
+		t.Errorf("unexpected test.UUID: %+v", test.UUID)
+	}
+	if out, _ := json.Marshal(test); string(out) != string(b) {
+		t.Errorf("unexpected JSON: %s != %s", out, b)
+	}
+
+	if err := json.Unmarshal([]byte(`{"uuid":"invalid"}`), &test); err == nil {
+		t.Errorf("Unmarshal JSON should error with an invalid UUID")
+	}
+
+	// Nullable UUIDs can always be nullified
+	if err := json.Unmarshal([]byte(`{"uuid":null}`), &test); err != nil {
+		t.Errorf("Unmarshal JSON should not error when given null")
+	}
+	if test.UUID.Valid {
+		t.Errorf("test.UUID should not be valid")
+	}
+	if out, _ := json.Marshal(test); string(out) != `{"uuid":null}` {
+		t.Errorf(`unexpected JSON: %s != {"uuid":null}`, out)
+	}
+
+	var uuid NullUUID
+	if err := uuid.Scan(nil); err != nil {
+		t.Errorf("Scan should not error with NULL: %s", err)
+	}
+	if value, _ := uuid.Value(); value != nil {
+		t.Errorf("Value should return nil for an invalid UUID")
+	}
+	if err := uuid.Scan(id[:]); err != nil {
+		t.Errorf("Scan should not error: %s", err)
+	}
+	if !uuid.Valid || uuid.UUID != id {
+		t.Errorf("unexpected scanned UUID: %+v", uuid)
+	}
+}
+
+func TestNullUUID_Text(t *testing.T) {
+	id := NewUUID()
+
+	// Decoders such as those of forms use encoding.TextUnmarshaler
+	var uuid NullUUID
+	var unmarshaler encoding.TextUnmarshaler = &uuid
+	if err := unmarshaler.UnmarshalText([]byte(id.String())); err != nil {
+		t.Fatalf("Unmarshal text should not error: %s", err)
+	}
+	if !uuid.Valid || uuid.UUID != id {
+		t.Errorf("unexpected UUID: %+v", uuid)
+	}
+	if value, _ := uuid.Value(); value != id.String() {
+		t.Errorf("unexpected value: %v != %s", value, id)
+	}
+	if err := unmarshaler.UnmarshalText([]byte{}); err != nil {
+		t.Errorf("Unmarshal text should not error with empty text: %s", err)
+	}
+	if uuid.Valid {
+		t.Errorf("uuid should not be valid after empty text")
+	}
+	if err := unmarshaler.UnmarshalText([]byte("invalid")); err == nil {
+		t.Errorf("Unmarshal text should error with an invalid UUID")
+	}
+
+	type item struct {
+		UUID NullUUID `xml:"uuid"`
+	}
+	b, _ := xml.Marshal(item{})
+	if string(b) != "<item><uuid></uuid></item>" {
+		t.Errorf("unexpected XML: %s", b)
+	}
+	var decoded item
+	if err := xml.Unmarshal(b, &decoded); err != nil {
+		t.Fatalf("Unmarshal XML should not error: %s", err)
+	}
+	if decoded.UUID.Valid {
+		t.Errorf("an empty XML element should not be valid")
+	}
+
+	b, _ = xml.Marshal(item{UUID: NullUUID{UUID: id, Valid: true}})
+	if err := xml.Unmarshal(b, &decoded); err != nil {
+		t.Fatalf("Unmarshal XML should not error: %s", err)
+	}
+	if !decoded.UUID.Valid || decoded.UUID.UUID != id {
+		t.Errorf("unexpected XML UUID: %+v", decoded.UUID)
+	}
+
+	if b, _ := (NullUUID{}).MarshalBinary(); len(b) != 0 {
+		t.Errorf("invalid UUIDs should marshal to no bytes")
+	}
+	if err := uuid.UnmarshalBinary(id[:]); err != nil || !uuid.Valid {
+		t.Errorf("Unmarshal binary should set a valid UUID: %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+package fields
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	neturl "net/url"
+	"strings"
+	"sync"
+)
+
+// RuleURLRedirect is reported when a RedirectURL could send the user to
+// another site
+const RuleURLRedirect URLRule = "redirect"
+
+var redirectHosts = struct {
+	sync.RWMutex
+	hosts domainSet
+}{}
+
+// AllowRedirectHosts adds hosts that absolute RedirectURLs can use when
+// unmarshaled or created with NewRedirectURL. Hosts can be wildcards such
+// as *.example.com.
+func AllowRedirectHosts(hosts ...string) {
+	redirectHosts.Lock()
+	defer redirectHosts.Unlock()
+	for _, host := range hosts {
+		redirectHosts.hosts.add(host)
+	}
+}
+
+// RedirectURL is a redirect target, such as the next parameter of a login
+// form, that cannot send the user to another site. It must either be a
+// relative path, such as /dashboard, or an http or https URL with an
+// allowed host (see AllowRedirectHosts).
+type RedirectURL URL
+
+// URL returns the RedirectURL as a URL
+func (url RedirectURL) URL() URL {
+	return URL(url)
+}
+
+// UnmarshalJSON will error if the URL is not a safe redirect target
+func (url *RedirectURL) UnmarshalJSON(text []byte) error {
+	b := bytes.NewBuffer(text)
+	dec := json.NewDecoder(b)
+	var n string
+	if err := dec.Decode(&n); err != nil {
+		return err
+	}
+	return url.UnmarshalText([]byte(n))
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface for form
+// values. It will error if the URL is not a safe redirect target.
+func (url *RedirectURL) UnmarshalText(text []byte) error {
+	parsed, err := NewRedirectURL(string(text))
+	if err != nil {
+		return err
+	}
+	*url = parsed
+	return nil
+}
+
+func redirectError(reason string) error {
+	return URLError{Rule: RuleURLRedirect, Reason: reason}
+}
+
+// hasUnsafeEncoding returns true if the string contains a percent-encoded
+// control character or backslash
+func hasUnsafeEncoding(s string) bool {
+	for i := 0; i+2 < len(s); i++ {
+		if s[i] != '%' {
+			continue
+		}
+		if c, ok := xtob(s[i+1:]); ok && (c < 0x20 || c == 0x7f || c == '\\') {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseRedirectURL creates a new RedirectURL that allows absolute URLs
+// with the given hosts
+func ParseRedirectURL(url string, hosts ...string) (RedirectURL, error) {
+	var allowed domainSet
+	for _, host := range hosts {
+		allowed.add(host)
+	}
+	return parseRedirectURL(url, allowed)
+}
+
+func parseRedirectURL(url string, allowed domainSet) (RedirectURL, error) {
+	url = strings.TrimSpace(url)
+	if url == "" {
+		return "", redirectError("Redirect URLs cannot be empty")
+	}
+	for i := 0; i < len(url); i++ {
+		if url[i] < 0x20 || url[i] == 0x7f {
+			return "", redirectError("Redirect URLs cannot contain control characters")
+		}
+		if url[i] == '\\' {
+			return "", redirectError("Redirect URLs cannot contain backslashes")
+		}
+	}
+	if hasUnsafeEncoding(url) {
+		return "", redirectError(
+			"Redirect URLs cannot contain encoded control characters or backslashes",
+		)
+	}
+
+	parsed, err := neturl.Parse(url)
+	if err != nil {
+		return "", URLError{Rule: RuleURLSyntax, Reason: err.Error()}
+	}
+	if parsed.Scheme == "" && parsed.Host == "" {
+		if !strings.HasPrefix(url, "/") || strings.HasPrefix(url, "//") {
+			return "", redirectError("Redirect URLs must be paths beginning with a single '/'")
+		}
+		if strings.HasPrefix(strings.ToLower(url), "/%2f") {
+			return "", redirectError("Redirect URLs cannot begin with an encoded '/'")
+		}
+		return RedirectURL(url), nil
+	}
+
+	scheme := strings.ToLower(parsed.Scheme)
+	if scheme != "http" && scheme != "https" {
+		return "", redirectError("Redirect URLs must be paths or http or https URLs")
+	}
+	if parsed.User != nil {
+		return "", redirectError("Redirect URLs cannot contain user information")
+	}
+	host := strings.TrimSuffix(strings.ToLower(parsed.Hostname()), ".")
+	if host == "" || !allowed.matches(policyDomain(host)) {
+		return "", redirectError(fmt.Sprintf(
+			"Redirect URLs cannot use the host '%s'", parsed.Hostname(),
+		))
+	}
+	return RedirectURL(url), nil
+}
+
+// NewRedirectURL creates a new RedirectURL that allows absolute URLs with
+// the hosts added by AllowRedirectHosts
+func NewRedirectURL(url string) (RedirectURL, error) {
+	redirectHosts.RLock()
+	defer redirectHosts.RUnlock()
+	return parseRedirectURL(url, redirectHosts.hosts)
+}
@@ -0,0 +1,76 @@
+package fields
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRedirectURL(t *testing.T) {
+	valid := []string{
+		"/",
+		"/dashboard?tab=1#top",
+		"/a/%20b",
+		"https://app.example.com/welcome",
+		"HTTP://APP.EXAMPLE.COM/",
+		"https://eu.accounts.example.com/",
+	}
+	for _, url := range valid {
+		if _, err := ParseRedirectURL(url, "app.example.com", "*.accounts.example.com"); err != nil {
+			t.Errorf("ParseRedirectURL(%q) should not error: %s", url, err)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"//evil.com",
+		"///evil.com",
+		"/\\evil.com",
+		"\\\\evil.com",
+		"/%5cevil.com",
+		"/%2f%2fevil.com",
+		"/a%0d%0aSet-Cookie:x=1",
+		"/a\tb",
+		"dashboard",
+		"https://evil.com/",
+		"https://app.example.com.evil.com/",
+		"https://app.example.com@evil.com/",
+		"https://evil.com\\@app.example.com/",
+		"https://accounts.example.com/",
+		"javascript:alert(1)",
+		"http:/app.example.com",
+		"ftp://app.example.com/",
+	}
+	for _, url := range invalid {
+		if _, err := ParseRedirectURL(url, "app.example.com", "*.accounts.example.com"); err == nil {
+			t.Errorf("ParseRedirectURL(%q) should error", url)
+		}
+	}
+}
+
+func TestRedirectURL_Unmarshal(t *testing.T) {
+	AllowRedirectHosts("trusted.example.org")
+
+	var next RedirectURL
+	if err := json.Unmarshal([]byte(`"https://trusted.example.org/a"`), &next); err != nil {
+		t.Errorf("Unmarshal JSON should not error: %s", err)
+	}
+	if next.URL().Path() != "/a" {
+		t.Errorf("unexpected path: %s", next.URL().Path())
+	}
+	if err := json.Unmarshal([]byte(`"//evil.com"`), &next); err == nil {
+		t.Errorf("Unmarshal JSON should error with a scheme-relative URL")
+	}
+
+	if err := next.UnmarshalText([]byte("/settings")); err != nil {
+		t.Errorf("Unmarshal text should not error: %s", err)
+	}
+	if next != "/settings" {
+		t.Errorf("unexpected redirect: %s != /settings", next)
+	}
+	if err := next.UnmarshalText([]byte("https://evil.com")); err == nil {
+		t.Errorf("Unmarshal text should error with a host that is not allowed")
+	}
+	if next != "/settings" {
+		t.Errorf("redirect should be unchanged after an error")
+	}
+}
@@ -0,0 +1,192 @@
+package fields
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// RuleURLUnsafe is reported when a SafeURL's host is, or resolves to, an
+// IP address that the server should not fetch
+const RuleURLUnsafe URLRule = "unsafe"
+
+// unsafePrefixes are blocked in addition to the loopback, private,
+// link-local, multicast and unspecified addresses
+var unsafePrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),         // "This" network
+	netip.MustParsePrefix("100.64.0.0/10"),     // Shared address space
+	netip.MustParsePrefix("168.63.129.16/32"),  // Azure metadata
+	netip.MustParsePrefix("192.0.0.0/24"),      // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"),     // Benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),       // Reserved and broadcast
+	netip.MustParsePrefix("64:ff9b::/96"),      // NAT64
+	netip.MustParsePrefix("64:ff9b:1::/48"),    // Local-use NAT64
+	netip.MustParsePrefix("2001:db8::/32"),     // Documentation
+	netip.MustParsePrefix("100::/64"),          // Discard-only
+	netip.MustParsePrefix("::ffff:0:0:0/96"),   // IPv4-translated
+	netip.MustParsePrefix("2002::/16"),         // 6to4, can embed private IPv4
+	netip.MustParsePrefix("fd00:ec2::254/128"), // AWS IPv6 metadata
+}
+
+// IsUnsafeIP returns true if the IP address is loopback, private,
+// link-local (including the 169.254.169.254 metadata service), multicast,
+// unspecified or otherwise reserved
+func IsUnsafeIP(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsValid() || ip.IsLoopback() || ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return true
+	}
+	for _, prefix := range unsafePrefixes {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// IPResolver resolves hostnames for SafeURL.Check. It is satisfied by
+// *net.Resolver.
+type IPResolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+var _ IPResolver = &net.Resolver{}
+
+// SafeURL is an http or https URL that is safe for the server to fetch,
+// such as a user-supplied webhook. Its host cannot be an unsafe IP address
+// (see IsUnsafeIP) and Check will also reject hosts that resolve to one.
+// Since DNS answers can change between the check and the request, fetch
+// SafeURLs with SafeTransport.
+type SafeURL URL
+
+// URL returns the SafeURL as a URL
+func (url SafeURL) URL() URL {
+	return URL(url)
+}
+
+// Check resolves the URL's host with the given resolver and will error if
+// any of its addresses are unsafe
+func (url SafeURL) Check(ctx context.Context, resolver IPResolver) error {
+	host, err := checkSafeURL(string(url))
+	if err != nil {
+		return err
+	}
+	if _, err := netip.ParseAddr(host); err == nil {
+		return nil
+	}
+	addrs, err := resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return err
+	}
+	if len(addrs) == 0 {
+		return fmt.Errorf("URL host '%s' has no addresses", host)
+	}
+	for _, addr := range addrs {
+		ip, ok := netip.AddrFromSlice(addr.IP)
+		if !ok || IsUnsafeIP(ip) {
+			return URLError{
+				Rule: RuleURLUnsafe,
+				Reason: fmt.Sprintf(
+					"URL host '%s' resolves to the unsafe address %s", host, addr.IP,
+				),
+			}
+		}
+	}
+	return nil
+}
+
+// Scan converts an SQL value into a SafeURL
+func (url *SafeURL) Scan(value interface{}) error {
+	return (*URL)(url).Scan(value)
+}
+
+// Value returns the SafeURL as a string
+func (url SafeURL) Value() (driver.Value, error) {
+	return URL(url).Value()
+}
+
+// UnmarshalJSON will error if the URL is not an http or https URL, or if
+// its host is an unsafe IP address. It does not resolve the host.
+func (url *SafeURL) UnmarshalJSON(text []byte) error {
+	var parsed URL
+	if err := parsed.UnmarshalJSON(text); err != nil {
+		return err
+	}
+	if _, err := checkSafeURL(string(parsed)); err != nil {
+		return err
+	}
+	*url = SafeURL(parsed)
+	return nil
+}
+
+// checkSafeURL performs the checks that do not need a resolver and
+// returns the URL's hostname
+func checkSafeURL(url string) (string, error) {
+	parsed, err := ParseURL(url, AllowedSchemes("http", "https"), RequireHost())
+	if err != nil {
+		return "", err
+	}
+	host := strings.TrimSuffix(strings.ToLower(parsed.Hostname()), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return "", URLError{
+			Rule: RuleURLUnsafe, Reason: "URL hosts cannot be localhost",
+		}
+	}
+	if ip, err := netip.ParseAddr(host); err == nil && IsUnsafeIP(ip) {
+		return "", URLError{
+			Rule:   RuleURLUnsafe,
+			Reason: fmt.Sprintf("URL hosts cannot be the unsafe address %s", ip),
+		}
+	}
+	return host, nil
+}
+
+// NewSafeURL creates a new SafeURL. It does not resolve the host - use
+// Check before fetching.
+func NewSafeURL(url string) (SafeURL, error) {
+	if _, err := checkSafeURL(url); err != nil {
+		return "", err
+	}
+	return SafeURL(strings.TrimSpace(url)), nil
+}
+
+// SafeDialControl can be used as the Control function of a net.Dialer. It
+// refuses connections to unsafe IP addresses after DNS resolution, which
+// prevents DNS rebinding.
+func SafeDialControl(network, address string, c syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	if IsUnsafeIP(ip) {
+		return fmt.Errorf("Connections to the unsafe address %s are not allowed", ip)
+	}
+	return nil
+}
+
+// SafeTransport returns an http.Transport that refuses connections to
+// unsafe IP addresses. Proxies are disabled, since a proxy would make the
+// connection on the transport's behalf.
+func SafeTransport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   SafeDialControl,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return transport
+}
@@ -0,0 +1,100 @@
+package fields
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+func TestIsUnsafeIP(t *testing.T) {
+	unsafe := []string{
+		"127.0.0.1", "10.1.2.3", "172.16.0.1", "192.168.1.1",
+		"169.254.169.254", "100.100.100.200", "0.0.0.0", "255.255.255.255",
+		"::1", "fe80::1", "fc00::1", "fd00:ec2::254", "::ffff:127.0.0.1",
+		"224.0.0.1",
+	}
+	for _, ip := range unsafe {
+		if !IsUnsafeIP(netip.MustParseAddr(ip)) {
+			t.Errorf("%s should be unsafe", ip)
+		}
+	}
+	safe := []string{"93.184.216.34", "8.8.8.8", "2606:2800:220:1::1"}
+	for _, ip := range safe {
+		if IsUnsafeIP(netip.MustParseAddr(ip)) {
+			t.Errorf("%s should be safe", ip)
+		}
+	}
+}
+
+func TestSafeURL(t *testing.T) {
+	invalid := []string{
+		"http://169.254.169.254/latest/meta-data",
+		"http://127.0.0.1:8080/",
+		"http://[::1]/",
+		"http://localhost/",
+		"http://api.localhost./",
+		"ftp://example.com/",
+		"/relative",
+	}
+	for _, url := range invalid {
+		if _, err := NewSafeURL(url); err == nil {
+			t.Errorf("NewSafeURL(%q) should error", url)
+		}
+	}
+
+	var url SafeURL
+	if err := json.Unmarshal([]byte(`"http://10.0.0.1/hook"`), &url); err == nil {
+		t.Errorf("Unmarshal JSON should error with an unsafe URL")
+	}
+	if err := json.Unmarshal([]byte(`"https://hooks.example.com/a"`), &url); err != nil {
+		t.Fatalf("Unmarshal JSON should not error: %s", err)
+	}
+	if url.URL().Host() != "hooks.example.com" {
+		t.Errorf("unexpected host: %s", url.URL().Host())
+	}
+
+	resolver := &fakeResolver{
+		addrs: map[string][]net.IPAddr{
+			"hooks.example.com": {{IP: net.ParseIP("93.184.216.34")}},
+			"rebind.example.com": {
+				{IP: net.ParseIP("93.184.216.34")},
+				{IP: net.ParseIP("169.254.169.254")},
+			},
+		},
+	}
+	ctx := context.Background()
+	if err := url.Check(ctx, resolver); err != nil {
+		t.Errorf("Check should not error: %s", err)
+	}
+
+	rebind, err := NewSafeURL("https://rebind.example.com/")
+	if err != nil {
+		t.Fatalf("NewSafeURL should not error: %s", err)
+	}
+	var urlErr URLError
+	if err := rebind.Check(ctx, resolver); !errors.As(err, &urlErr) || urlErr.Rule != RuleURLUnsafe {
+		t.Errorf("Check should return an unsafe URLError: %v", err)
+	}
+
+	missing, _ := NewSafeURL("https://missing.example.com/")
+	if err := missing.Check(ctx, resolver); err == nil {
+		t.Errorf("Check should error when the host cannot be resolved")
+	}
+}
+
+func TestSafeTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {},
+	))
+	defer server.Close()
+
+	client := &http.Client{Transport: SafeTransport()}
+	if _, err := client.Get(server.URL); err == nil {
+		t.Errorf("SafeTransport should refuse connections to loopback addresses")
+	}
+}
@@ -0,0 +1,78 @@
+package fields
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aodin/sol"
+)
+
+// FK is a foreign key that can load the row it references into a T, such
+// as a User struct for a user_id column. It embeds an ImmutableFK, so the
+// column, database and JSON input behave the same.
+type FK[T any] struct {
+	ImmutableFK
+
+	// Expand embeds the loaded row in MarshalJSON instead of the ID
+	Expand bool
+
+	cached   *T
+	cachedID uint64
+}
+
+var _ sol.Modifier = FK[struct{}]{}
+
+// Load selects the referenced row into a T. The row is cached, and later
+// calls return the cached row until the ID changes. sol connections do not
+// accept a context, so the context is only checked before the query.
+func (fk *FK[T]) Load(ctx context.Context, conn sol.Conn) (T, error) {
+	if value, ok := fk.Cached(); ok {
+		return value, nil
+	}
+	var value T
+	if fk.Table == nil {
+		return value, fmt.Errorf("foreign keys must have a table to be loaded")
+	}
+	if fk.ID == 0 {
+		return value, fmt.Errorf("foreign keys cannot be loaded with a zero ID")
+	}
+	if err := ctx.Err(); err != nil {
+		return value, err
+	}
+	stmt := fk.Table.Select().Where(
+		fk.Table.C("id").Equals(fk.ID),
+	).Limit(1)
+	if err := conn.Query(stmt, &value); err != nil {
+		return value, err
+	}
+	fk.Cache(value)
+	return value, nil
+}
+
+// Cached returns the loaded row and true if it has been loaded for the
+// current ID
+func (fk FK[T]) Cached() (T, bool) {
+	if fk.cached == nil || fk.cachedID != fk.ID {
+		var zero T
+		return zero, false
+	}
+	return *fk.cached, true
+}
+
+// Cache sets the loaded row for the current ID, such as a row selected
+// with a join
+func (fk *FK[T]) Cache(value T) {
+	fk.cached, fk.cachedID = &value, fk.ID
+}
+
+// MarshalJSON returns the ID, or the loaded row if Expand is set and the
+// row has been loaded
+func (fk FK[T]) MarshalJSON() ([]byte, error) {
+	if fk.Expand {
+		if value, ok := fk.Cached(); ok {
+			return json.Marshal(value)
+		}
+	}
+	return fk.ImmutableFK.MarshalJSON()
+}
@@ -0,0 +1,108 @@
+package fields
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	sql "github.com/aodin/sol"
+)
+
+type fkUser struct {
+	ID   uint64 `db:"id" json:"id"`
+	Name string `db:"name" json:"name"`
+}
+
+func (user fkUser) GetID() uint64 { return user.ID }
+
+var FKUsers = sql.Table("fk_users", Serial{})
+
+// fakeConn calls query for each statement. It embeds sol.Conn to satisfy
+// the rest of the interface.
+type fakeConn struct {
+	sql.Conn
+	query   func(dest ...interface{}) error
+	queries int
+}
+
+func (conn *fakeConn) Query(stmt sql.Executable, dest ...interface{}) error {
+	conn.queries++
+	return conn.query(dest...)
+}
+
+func TestFK_Load(t *testing.T) {
+	conn := &fakeConn{query: func(dest ...interface{}) error {
+		*(dest[0].(*fkUser)) = fkUser{ID: 1, Name: "admin"}
+		return nil
+	}}
+
+	var fk FK[fkUser]
+	if _, err := fk.Load(context.Background(), conn); err == nil {
+		t.Errorf("Load should error without a table")
+	}
+	fk.SetTable("user_id", FKUsers)
+	if _, err := fk.Load(context.Background(), conn); err == nil {
+		t.Errorf("Load should error with a zero ID")
+	}
+
+	if err := json.Unmarshal([]byte(`1`), &fk); err != nil {
+		t.Fatalf("Unmarshal JSON should not error: %s", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := fk.Load(ctx, conn); err == nil {
+		t.Errorf("Load should error with a cancelled context")
+	}
+
+	user, err := fk.Load(context.Background(), conn)
+	if err != nil {
+		t.Fatalf("Load should not error: %s", err)
+	}
+	if user.Name != "admin" {
+		t.Errorf("unexpected user: %+v", user)
+	}
+	if _, err := fk.Load(context.Background(), conn); err != nil {
+		t.Errorf("Load should not error: %s", err)
+	}
+	if conn.queries != 1 {
+		t.Errorf("Load should use the cache: %d queries", conn.queries)
+	}
+
+	failing := &fakeConn{query: func(dest ...interface{}) error {
+		return fmt.Errorf("no rows")
+	}}
+	other := FK[fkUser]{ImmutableFK: ImmutableFK{ID: 2, Table: FKUsers}}
+	if _, err := other.Load(context.Background(), failing); err == nil {
+		t.Errorf("Load should error when the query errors")
+	}
+	if _, ok := other.Cached(); ok {
+		t.Errorf("failed loads should not be cached")
+	}
+}
+
+func TestFK_MarshalJSON(t *testing.T) {
+	fk := FK[fkUser]{ImmutableFK: ImmutableFK{ID: 1}}
+	b, _ := json.Marshal(fk)
+	if string(b) != `1` {
+		t.Errorf("unexpected JSON: %s", b)
+	}
+
+	// Expanded keys marshal the ID until the row is loaded
+	fk.Expand = true
+	b, _ = json.Marshal(fk)
+	if string(b) != `1` {
+		t.Errorf("unexpected JSON: %s", b)
+	}
+	fk.Cache(fkUser{ID: 1, Name: "admin"})
+	b, _ = json.Marshal(fk)
+	if string(b) != `{"id":1,"name":"admin"}` {
+		t.Errorf("unexpected JSON: %s", b)
+	}
+
+	// The cache belongs to the ID
+	fk.ID = 2
+	if _, ok := fk.Cached(); ok {
+		t.Errorf("the cache should not be used for a different ID")
+	}
+}
@@ -0,0 +1,167 @@
+package fields
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/aodin/sol"
+)
+
+// ULID is a Universally Unique Lexicographically Sortable Identifier: a 48
+// bit Unix timestamp in milliseconds followed by 80 random bits. It has the
+// same 128 bits as a UUID and is stored in a UUID column.
+// https://github.com/ulid/spec
+type ULID [16]byte
+
+// String returns the 26 character Crockford base32 form of the ULID
+func (ulid ULID) String() string {
+	return encodeBase(ulid, crockfordAlphabet, base32Length)
+}
+
+// Time returns the timestamp of the ULID
+func (ulid ULID) Time() time.Time {
+	ms := uint64(ulid[0])<<40 | uint64(ulid[1])<<32 | uint64(ulid[2])<<24 |
+		uint64(ulid[3])<<16 | uint64(ulid[4])<<8 | uint64(ulid[5])
+	return time.UnixMilli(int64(ms)).UTC()
+}
+
+// UUID returns the ULID as a UUID with the same bits. The UUID will not
+// have a valid version or variant.
+func (ulid ULID) UUID() UUID {
+	return UUID(ulid)
+}
+
+// ULID returns the UUID as a ULID with the same bits
+func (uuid UUID) ULID() ULID {
+	return ULID(uuid)
+}
+
+// Equals returns true if the ULIDs are equal
+func (ulid ULID) Equals(other ULID) bool {
+	return ulid == other
+}
+
+// MarshalText implements the encoding.TextMarshaler interface
+func (ulid ULID) MarshalText() ([]byte, error) {
+	return []byte(ulid.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface. Empty
+// text is ignored.
+func (ulid *ULID) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		return nil
+	}
+	parsed, err := ParseULID(string(text))
+	if err != nil {
+		return err
+	}
+	*ulid = parsed
+	return nil
+}
+
+// MarshalJSON returns the ULID as a JSON string
+func (ulid ULID) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + ulid.String() + `"`), nil
+}
+
+// UnmarshalJSON parses a ULID from a JSON string. Empty strings are ignored.
+func (ulid *ULID) UnmarshalJSON(data []byte) error {
+	if len(data) < 2 || data[0] != '"' || data[len(data)-1] != '"' {
+		return fmt.Errorf("ULIDs must be a JSON string")
+	}
+	return ulid.UnmarshalText(data[1 : len(data)-1])
+}
+
+// Scan converts an SQL value into a ULID. UUID columns are scanned as
+// UUIDs, and text values of 26 characters are parsed as ULIDs.
+func (ulid *ULID) Scan(value interface{}) error {
+	var text string
+	switch v := value.(type) {
+	case string:
+		text = v
+	case []byte:
+		text = string(v)
+	}
+	if len(text) == base32Length {
+		parsed, err := ParseULID(text)
+		if err != nil {
+			return err
+		}
+		*ulid = parsed
+		return nil
+	}
+	return (*UUID)(ulid).Scan(value)
+}
+
+// Value returns the ULID in the canonical form of a UUID for insert into
+// a UUID column
+func (ulid ULID) Value() (driver.Value, error) {
+	return UUID(ulid).Value()
+}
+
+var _ sol.Modifier = ULID{}
+
+// Modify adds a UUID column named ulid to the table
+func (ulid ULID) Modify(table sol.Tabular) error {
+	return sol.Column("ulid", UUIDv4).Modify(table)
+}
+
+// ParseULID parses the Crockford base32 form of a ULID. Decoding ignores
+// case and reads I and L as 1 and O as 0.
+func ParseULID(s string) (ULID, error) {
+	return decodeBase(s, &crockfordValues, 32, base32Length, "ULIDs")
+}
+
+// NewULID creates a ULID from the current time. ULIDs created by NewULID
+// are strictly increasing - see UUIDGenerator.
+func NewULID() ULID {
+	ulid, err := DefaultUUIDGenerator().NewULID()
+	if err != nil {
+		log.Panic(err) // rand should never fail
+	}
+	return ulid
+}
+
+// NewULID creates a ULID that is greater than all previous ULIDs created
+// by the generator. Within the same millisecond, the random bits of the
+// previous ULID are incremented.
+func (gen *UUIDGenerator) NewULID() (ulid ULID, err error) {
+	gen.mu.Lock()
+	defer gen.mu.Unlock()
+
+	ms := uint64(gen.clock().UnixMilli())
+	if gen.lastULID == (ULID{}) || ms > gen.lastULIDMS {
+		if _, err = io.ReadFull(gen.entropy, ulid[6:]); err != nil {
+			return ULID{}, err
+		}
+	} else {
+		ms, ulid = gen.lastULIDMS, gen.lastULID
+		if !incrementBytes(ulid[6:]) {
+			// The random bits rolled over, so borrow the next millisecond
+			ms++
+			if _, err = io.ReadFull(gen.entropy, ulid[6:]); err != nil {
+				return ULID{}, err
+			}
+		}
+	}
+	ulid[0], ulid[1], ulid[2] = byte(ms>>40), byte(ms>>32), byte(ms>>24)
+	ulid[3], ulid[4], ulid[5] = byte(ms>>16), byte(ms>>8), byte(ms)
+	gen.lastULID, gen.lastULIDMS = ulid, ms
+	return
+}
+
+// incrementBytes adds one to the big-endian number, returning false if it
+// overflowed
+func incrementBytes(b []byte) bool {
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i]++
+		if b[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
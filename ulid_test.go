@@ -0,0 +1,139 @@
+package fields
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/rand"
+	"testing"
+	"time"
+
+	sql "github.com/aodin/sol"
+)
+
+var ULIDTests = sql.Table("ulid_tests",
+	ULID{},
+)
+
+func TestULID(t *testing.T) {
+	ulid, err := ParseULID("01ARZ3NDEKTSV4RRFFQ69G5FAV")
+	if err != nil {
+		t.Fatalf("ParseULID should not error: %s", err)
+	}
+	if ulid.String() != "01ARZ3NDEKTSV4RRFFQ69G5FAV" {
+		t.Errorf("unexpected ULID: %s", ulid)
+	}
+	expected := time.Date(2016, 7, 30, 23, 54, 10, 259e6, time.UTC)
+	if !ulid.Time().Equal(expected) {
+		t.Errorf("unexpected time: %s != %s", ulid.Time(), expected)
+	}
+
+	// Parsing ignores case
+	lower, err := ParseULID("01arz3ndektsv4rrffq69g5fav")
+	if err != nil || lower != ulid {
+		t.Errorf("unexpected lowercase ULID: %s %v", lower, err)
+	}
+
+	invalid := []string{
+		"",
+		"01ARZ3NDEKTSV4RRFFQ69G5FA",
+		"01ARZ3NDEKTSV4RRFFQ69G5FAU",
+		"81ARZ3NDEKTSV4RRFFQ69G5FAV",
+	}
+	for _, s := range invalid {
+		if _, err := ParseULID(s); err == nil {
+			t.Errorf("ParseULID(%q) should error", s)
+		}
+	}
+
+	// Conversion to and from UUIDs is lossless
+	if ulid.UUID().ULID() != ulid {
+		t.Errorf("ULID should round trip through UUID: %s", ulid)
+	}
+	uuid := NewUUID()
+	if uuid.ULID().UUID() != uuid {
+		t.Errorf("UUID should round trip through ULID: %s", uuid)
+	}
+}
+
+func TestULID_Encoding(t *testing.T) {
+	ulid := NewULID()
+	test := struct {
+		ID ULID `json:"id"`
+	}{ID: ulid}
+
+	b, err := json.Marshal(test)
+	if err != nil {
+		t.Fatalf("JSON marshal should not error: %s", err)
+	}
+	if string(b) != `{"id":"`+ulid.String()+`"}` {
+		t.Errorf("unexpected JSON: %s", b)
+	}
+	test.ID = ULID{}
+	if err := json.Unmarshal(b, &test); err != nil {
+		t.Fatalf("JSON unmarshal should not error: %s", err)
+	}
+	if test.ID != ulid {
+		t.Errorf("unexpected ULID: %s != %s", test.ID, ulid)
+	}
+	if err := json.Unmarshal([]byte(`{"id":1}`), &test); err == nil {
+		t.Errorf("JSON unmarshal should error with a number")
+	}
+
+	// The database stores ULIDs in UUID columns
+	value, _ := ulid.Value()
+	if value != ulid.UUID().String() {
+		t.Errorf("unexpected value: %v", value)
+	}
+	var scanned ULID
+	if err := scanned.Scan([]byte(ulid.UUID().String())); err != nil {
+		t.Errorf("Scan should not error: %s", err)
+	}
+	if scanned != ulid {
+		t.Errorf("unexpected scanned ULID: %s != %s", scanned, ulid)
+	}
+	scanned = ULID{}
+	if err := scanned.Scan(ulid.String()); err != nil || scanned != ulid {
+		t.Errorf("Scan should accept ULID text: %s %v", scanned, err)
+	}
+	if err := scanned.Scan(nil); err == nil {
+		t.Errorf("Scan should error with NULL")
+	}
+}
+
+func TestUUIDGenerator_ULID(t *testing.T) {
+	now := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	gen := NewUUIDGenerator(
+		UUIDClock(func() time.Time { return now }),
+		UUIDEntropy(rand.New(rand.NewSource(1))),
+	)
+
+	prev, err := gen.NewULID()
+	if err != nil {
+		t.Fatalf("NewULID should not error: %s", err)
+	}
+	if !prev.Time().Equal(now) {
+		t.Errorf("unexpected time: %s != %s", prev.Time(), now)
+	}
+	for i := 0; i < 1000; i++ {
+		next, err := gen.NewULID()
+		if err != nil {
+			t.Fatalf("NewULID should not error: %s", err)
+		}
+		if bytes.Compare(prev[:], next[:]) >= 0 || prev.String() >= next.String() {
+			t.Fatalf("ULIDs should be strictly increasing: %s >= %s", prev, next)
+		}
+		prev = next
+	}
+
+	// A clock that moves backwards should not break the ordering
+	now = now.Add(-time.Hour)
+	next, _ := gen.NewULID()
+	if bytes.Compare(prev[:], next[:]) >= 0 {
+		t.Errorf("ULIDs should be strictly increasing: %s >= %s", prev, next)
+	}
+
+	failing := NewUUIDGenerator(UUIDEntropy(bytes.NewReader(nil)))
+	if _, err := failing.NewULID(); err == nil {
+		t.Errorf("NewULID should error when entropy fails")
+	}
+}
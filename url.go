@@ -4,16 +4,113 @@ import (
 	"bytes"
 	"database/sql/driver"
 	"encoding/json"
+	"fmt"
+	neturl "net/url"
+	"regexp"
 	"strings"
+
+	"github.com/aodin/sol"
+	"github.com/aodin/sol/types"
+)
+
+// URLRule names the validation that a URL failed
+type URLRule string
+
+// Rules that can be reported by a URLError
+const (
+	RuleURLSyntax   URLRule = "syntax"
+	RuleURLAbsolute URLRule = "absolute"
+	RuleURLScheme   URLRule = "scheme"
+	RuleURLHost     URLRule = "host"
 )
 
+// URLError is returned when a URL fails validation. Rule reports which
+// validation failed.
+type URLError struct {
+	Rule   URLRule
+	Reason string
+}
+
+// Error implements the error interface
+func (err URLError) Error() string {
+	return err.Reason
+}
+
+// URLOption configures the validation performed by NewValidURL and
+// ParseURL
+type URLOption func(*urlOptions)
+
+type urlOptions struct {
+	absolute bool
+	host     bool
+	schemes  []string
+}
+
+// RequireAbsolute requires the URL to have a scheme
+func RequireAbsolute() URLOption {
+	return func(opts *urlOptions) {
+		opts.absolute = true
+	}
+}
+
+// RequireHost requires the URL to have a host
+func RequireHost() URLOption {
+	return func(opts *urlOptions) {
+		opts.host = true
+	}
+}
+
+// AllowedSchemes requires the URL to have one of the given schemes. It
+// implies RequireAbsolute.
+func AllowedSchemes(schemes ...string) URLOption {
+	return func(opts *urlOptions) {
+		opts.absolute = true
+		for _, scheme := range schemes {
+			opts.schemes = append(opts.schemes, strings.ToLower(scheme))
+		}
+	}
+}
+
 // URL is a URL field
-// TODO Just boilerplate for now
 type URL string
 
+// Parsed returns the parsed URL. An empty URL is returned if the URL is
+// invalid.
+func (url URL) Parsed() *neturl.URL {
+	parsed, err := neturl.Parse(string(url))
+	if err != nil {
+		return &neturl.URL{}
+	}
+	return parsed
+}
+
+// Scheme returns the lowercased scheme of the URL, such as https
+func (url URL) Scheme() string {
+	return strings.ToLower(url.Parsed().Scheme)
+}
+
+// Host returns the host of the URL, including any port
+func (url URL) Host() string {
+	return url.Parsed().Host
+}
+
+// Path returns the unescaped path of the URL
+func (url URL) Path() string {
+	return url.Parsed().Path
+}
+
 // Scan converts an SQL value into a URL
 func (url *URL) Scan(value interface{}) error {
-	*url = URL(string(value.([]byte)))
+	switch v := value.(type) {
+	case []byte:
+		*url = URL(string(v))
+	case string:
+		*url = URL(v)
+	case nil:
+		return fmt.Errorf("URL scan returned NULL")
+	default:
+		return fmt.Errorf("URL scan returned unsupported type %T", value)
+	}
 	return nil
 }
 
@@ -22,7 +119,7 @@ func (url URL) Value() (driver.Value, error) {
 	return string(url), nil
 }
 
-// UnmarshalJSON
+// UnmarshalJSON trims spaces and will error if the URL cannot be parsed
 func (url *URL) UnmarshalJSON(text []byte) error {
 	b := bytes.NewBuffer(text)
 	dec := json.NewDecoder(b)
@@ -30,11 +127,121 @@ func (url *URL) UnmarshalJSON(text []byte) error {
 	if err := dec.Decode(&n); err != nil {
 		return err
 	}
-	*url = URL(strings.TrimSpace(n))
+	parsed, err := NewValidURL(n)
+	if err != nil {
+		return err
+	}
+	*url = parsed
 	return nil
 }
 
+// ParseURL trims spaces and parses the URL with net/url. Errors are of
+// type URLError.
+func ParseURL(url string, opts ...URLOption) (*neturl.URL, error) {
+	var options urlOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	parsed, err := neturl.Parse(strings.TrimSpace(url))
+	if err != nil {
+		return nil, URLError{Rule: RuleURLSyntax, Reason: err.Error()}
+	}
+	if options.absolute && !parsed.IsAbs() {
+		return nil, URLError{
+			Rule: RuleURLAbsolute, Reason: "URLs must be absolute",
+		}
+	}
+	if len(options.schemes) > 0 && !containsString(options.schemes, strings.ToLower(parsed.Scheme)) {
+		return nil, URLError{
+			Rule: RuleURLScheme,
+			Reason: fmt.Sprintf(
+				"URLs must have a scheme of %s", strings.Join(options.schemes, ", "),
+			),
+		}
+	}
+	if options.host && parsed.Hostname() == "" {
+		return nil, URLError{Rule: RuleURLHost, Reason: "URLs must have a host"}
+	}
+	return parsed, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
 // NewURL creates a new URL
 func NewURL(url string) URL {
 	return URL(url)
 }
+
+// NewValidURL creates a new URL, validating it with the given options.
+// Spaces are trimmed.
+func NewValidURL(url string, opts ...URLOption) (URL, error) {
+	if _, err := ParseURL(url, opts...); err != nil {
+		return "", err
+	}
+	return URL(strings.TrimSpace(url)), nil
+}
+
+// URLColumnElem is a Modifier for a URL column. It is created with
+// URLColumn.
+type URLColumnElem struct {
+	name    string
+	options columnOptions
+}
+
+var _ sol.Modifier = URLColumnElem{}
+
+// Type returns the column's type and constraints
+func (column URLColumnElem) Type() types.Type {
+	datatype := columnType{
+		err:     column.validate(),
+		name:    "TEXT",
+		notNull: column.options.notNull,
+		unique:  column.options.unique,
+	}
+	if column.options.limit > 0 {
+		datatype.name = fmt.Sprintf("VARCHAR(%d)", column.options.limit)
+	}
+	if column.options.check {
+		schemes := column.options.schemes
+		if len(schemes) == 0 {
+			schemes = []string{"http", "https"}
+		}
+		quoted := make([]string, len(schemes))
+		for i, scheme := range schemes {
+			quoted[i] = regexp.QuoteMeta(strings.ToLower(scheme))
+		}
+		datatype.constraints = append(datatype.constraints, fmt.Sprintf(
+			"%s ~* '^(%s):'", quoteIdentifier(column.name), strings.Join(quoted, "|"),
+		))
+	}
+	return datatype
+}
+
+func (column URLColumnElem) validate() error {
+	return column.options.supports(
+		"URL", "NotNull", "Unique", "Limit", "Check", "CheckSchemes",
+	)
+}
+
+// Modify implements the sol.Modifier interface. It will error if given an
+// unsupported option.
+func (column URLColumnElem) Modify(table sol.Tabular) error {
+	if err := column.validate(); err != nil {
+		return err
+	}
+	return sol.Column(column.name, column.Type()).Modify(table)
+}
+
+// URLColumn returns a Modifier for a URL column with the given name. It
+// accepts the NotNull, Unique, Limit, Check and CheckSchemes options. The
+// Check option allows http and https URLs.
+func URLColumn(name string, opts ...ColumnOption) URLColumnElem {
+	return URLColumnElem{name: name, options: newColumnOptions(opts)}
+}
@@ -0,0 +1,114 @@
+package fields
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	sql "github.com/aodin/sol"
+)
+
+var URLTests = sql.Table("url_tests",
+	Serial{},
+	URLColumn("homepage", Limit(2048), NotNull(), Check()),
+)
+
+func TestURL(t *testing.T) {
+	// NewURL does not validate
+	if NewURL("::") != "::" {
+		t.Errorf("NewURL should not modify the URL")
+	}
+
+	url, err := NewValidURL(" HTTPS://example.com:8080/a/b?c=d ")
+	if err != nil {
+		t.Fatalf("NewValidURL should not error: %s", err)
+	}
+	if url != "HTTPS://example.com:8080/a/b?c=d" {
+		t.Errorf("unexpected URL: %s", url)
+	}
+	if url.Scheme() != "https" {
+		t.Errorf("unexpected scheme: %s != https", url.Scheme())
+	}
+	if url.Host() != "example.com:8080" {
+		t.Errorf("unexpected host: %s != example.com:8080", url.Host())
+	}
+	if url.Path() != "/a/b" {
+		t.Errorf("unexpected path: %s != /a/b", url.Path())
+	}
+	if URL("%zz").Parsed() == nil {
+		t.Errorf("Parsed should not return nil for an invalid URL")
+	}
+
+	tests := []struct {
+		in   string
+		opts []URLOption
+		rule URLRule
+	}{
+		{in: "/relative", opts: []URLOption{RequireAbsolute()}, rule: RuleURLAbsolute},
+		{in: "%zz", rule: RuleURLSyntax},
+		{in: "ftp://example.com", opts: []URLOption{AllowedSchemes("http", "https")}, rule: RuleURLScheme},
+		{in: "/relative", opts: []URLOption{AllowedSchemes("https")}, rule: RuleURLAbsolute},
+		{in: "mailto:a@example.com", opts: []URLOption{RequireHost()}, rule: RuleURLHost},
+	}
+	for _, test := range tests {
+		_, err := NewValidURL(test.in, test.opts...)
+		var urlErr URLError
+		if !errors.As(err, &urlErr) {
+			t.Errorf("NewValidURL(%q) should return a URLError", test.in)
+			continue
+		}
+		if urlErr.Rule != test.rule {
+			t.Errorf("unexpected rule for %q: %s != %s", test.in, urlErr.Rule, test.rule)
+		}
+	}
+
+	if _, err := NewValidURL("HTTP://example.com", AllowedSchemes("http"), RequireHost()); err != nil {
+		t.Errorf("NewValidURL should not error: %s", err)
+	}
+}
+
+func TestURL_UnmarshalJSON(t *testing.T) {
+	var url URL
+	if err := json.Unmarshal([]byte(`" https://example.com "`), &url); err != nil {
+		t.Fatalf("Unmarshal JSON should not error: %s", err)
+	}
+	if url != "https://example.com" {
+		t.Errorf("unexpected URL: %s", url)
+	}
+	if err := json.Unmarshal([]byte(`"%zz"`), &url); err == nil {
+		t.Errorf("Unmarshal JSON should error with an invalid URL")
+	}
+
+	if err := url.Scan("https://example.org"); err != nil || url != "https://example.org" {
+		t.Errorf("Scan should accept strings: %v", err)
+	}
+	if err := url.Scan(nil); err == nil {
+		t.Errorf("Scan should error with NULL")
+	}
+}
+
+func TestURLColumn(t *testing.T) {
+	tests := []struct {
+		column URLColumnElem
+		out    string
+	}{
+		{column: URLColumn("homepage"), out: "TEXT"},
+		{
+			column: URLColumn("homepage", Limit(2048), NotNull(), Check()),
+			out:    `VARCHAR(2048) NOT NULL CHECK ("homepage" ~* '^(http|https):')`,
+		},
+		{
+			column: URLColumn("feed", CheckSchemes("https", "git+ssh")),
+			out:    `TEXT CHECK ("feed" ~* '^(https|git\+ssh):')`,
+		},
+	}
+	for _, test := range tests {
+		out, err := test.column.Type().Create(nil)
+		if err != nil {
+			t.Errorf("Create should not error: %s", err)
+		}
+		if out != test.out {
+			t.Errorf("unexpected column type: %s != %s", out, test.out)
+		}
+	}
+}
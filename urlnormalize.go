@@ -0,0 +1,202 @@
+package fields
+
+import (
+	neturl "net/url"
+	"sort"
+	"strings"
+)
+
+// URLNormalization is a set of RFC 3986 normalizations that can be
+// combined with the bitwise OR operator
+type URLNormalization uint
+
+// Normalizations performed by NormalizeURL
+const (
+	// NormalizeCase lowercases the scheme and host
+	NormalizeCase URLNormalization = 1 << iota
+	// NormalizePort removes the scheme's default port, such as :80 for http
+	NormalizePort
+	// NormalizeDotSegments resolves "." and ".." path segments
+	NormalizeDotSegments
+	// NormalizePercentEncoding uppercases percent-encodings and decodes
+	// those of unreserved characters
+	NormalizePercentEncoding
+	// NormalizeEmptyQuery removes a trailing "?" with no query
+	NormalizeEmptyQuery
+	// NormalizeEmptyPath sets an empty path to "/" when there is a host
+	NormalizeEmptyPath
+	// SortQuery sorts the query parameters by key. Parameters with the
+	// same key keep their order.
+	SortQuery
+	// StripFragment removes the fragment
+	StripFragment
+)
+
+// Normalization levels
+const (
+	// SafeNormalization does not change the semantics of the URL
+	SafeNormalization = NormalizeCase | NormalizePort | NormalizeDotSegments |
+		NormalizePercentEncoding | NormalizeEmptyQuery | NormalizeEmptyPath
+
+	// AggressiveNormalization may change the semantics of the URL for
+	// servers that depend on query order or fragments
+	AggressiveNormalization = SafeNormalization | SortQuery | StripFragment
+)
+
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+	"ws":    "80",
+	"wss":   "443",
+	"ftp":   "21",
+}
+
+// Normalize will perform an in-place normalization of the URL, only
+// returning an error if normalization fails. SafeNormalization is used
+// if no normalizations are given.
+func (url *URL) Normalize(normalizations ...URLNormalization) error {
+	var flags URLNormalization
+	for _, normalization := range normalizations {
+		flags |= normalization
+	}
+	if len(normalizations) == 0 {
+		flags = SafeNormalization
+	}
+	normalized, err := NormalizeURL(string(*url), flags)
+	if err != nil {
+		return err
+	}
+	*url = URL(normalized)
+	return nil
+}
+
+// NormalizeURL parses the URL and applies the given normalizations
+func NormalizeURL(url string, flags URLNormalization) (string, error) {
+	u, err := ParseURL(url)
+	if err != nil {
+		return "", err
+	}
+	if flags&NormalizeCase != 0 {
+		u.Scheme = strings.ToLower(u.Scheme)
+		u.Host = strings.ToLower(u.Host)
+	}
+	if flags&NormalizePort != 0 {
+		port := u.Port()
+		if port == "" || port == defaultPorts[strings.ToLower(u.Scheme)] {
+			u.Host = strings.TrimSuffix(u.Host, ":"+port)
+		}
+	}
+
+	path := u.EscapedPath()
+	// Dot segments of relative paths refer to the base URL, so only
+	// absolute paths are changed
+	if flags&NormalizeDotSegments != 0 && (u.Host != "" || strings.HasPrefix(path, "/")) {
+		path = removeDotSegments(path)
+	}
+	if flags&NormalizeEmptyPath != 0 && path == "" && u.Host != "" {
+		path = "/"
+	}
+	if flags&NormalizePercentEncoding != 0 {
+		path = normalizePercentEncoding(path)
+		u.RawQuery = normalizePercentEncoding(u.RawQuery)
+		u.RawFragment = normalizePercentEncoding(u.EscapedFragment())
+		u.Fragment, _ = neturl.PathUnescape(u.RawFragment)
+	}
+	if u.Path, err = neturl.PathUnescape(path); err != nil {
+		return "", URLError{Rule: RuleURLSyntax, Reason: err.Error()}
+	}
+	u.RawPath = path
+
+	if flags&NormalizeEmptyQuery != 0 && u.RawQuery == "" {
+		u.ForceQuery = false
+	}
+	if flags&SortQuery != 0 && u.RawQuery != "" {
+		params := strings.Split(u.RawQuery, "&")
+		sort.SliceStable(params, func(i, j int) bool {
+			return queryKey(params[i]) < queryKey(params[j])
+		})
+		u.RawQuery = strings.Join(params, "&")
+	}
+	if flags&StripFragment != 0 {
+		u.Fragment, u.RawFragment = "", ""
+	}
+	return u.String(), nil
+}
+
+func queryKey(param string) string {
+	key, _, _ := strings.Cut(param, "=")
+	return key
+}
+
+// removeDotSegments implements the algorithm of RFC 3986 section 5.2.4
+func removeDotSegments(path string) string {
+	var out []string
+	input := path
+	for input != "" {
+		switch {
+		case strings.HasPrefix(input, "../"):
+			input = input[3:]
+		case strings.HasPrefix(input, "./"):
+			input = input[2:]
+		case strings.HasPrefix(input, "/./"):
+			input = input[2:]
+		case input == "/.":
+			input = "/"
+		case strings.HasPrefix(input, "/../"):
+			input = input[3:]
+			if len(out) > 0 {
+				out = out[:len(out)-1]
+			}
+		case input == "/..":
+			input = "/"
+			if len(out) > 0 {
+				out = out[:len(out)-1]
+			}
+		case input == "." || input == "..":
+			input = ""
+		default:
+			// Move the first segment, including any leading slash
+			end := strings.IndexByte(input[1:], '/')
+			if end == -1 {
+				out = append(out, input)
+				input = ""
+			} else {
+				out = append(out, input[:end+1])
+				input = input[end+1:]
+			}
+		}
+	}
+	return strings.Join(out, "")
+}
+
+// isUnreserved returns true for the unreserved characters of RFC 3986
+// section 2.3, which never need to be percent-encoded
+func isUnreserved(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') ||
+		(c >= '0' && c <= '9') || c == '-' || c == '.' || c == '_' || c == '~'
+}
+
+// normalizePercentEncoding uppercases the hex digits of percent-encodings
+// and decodes the percent-encodings of unreserved characters
+func normalizePercentEncoding(s string) string {
+	if strings.IndexByte(s, '%') == -1 {
+		return s
+	}
+	var out strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '%' && i+2 < len(s) {
+			if c, ok := xtob(s[i+1:]); ok {
+				if isUnreserved(c) {
+					out.WriteByte(c)
+				} else {
+					out.WriteByte('%')
+					out.WriteString(strings.ToUpper(s[i+1 : i+3]))
+				}
+				i += 2
+				continue
+			}
+		}
+		out.WriteByte(s[i])
+	}
+	return out.String()
+}
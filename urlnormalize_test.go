@@ -0,0 +1,55 @@
+package fields
+
+import "testing"
+
+func TestURL_Normalize(t *testing.T) {
+	tests := []struct {
+		in, out string
+		flags   URLNormalization
+	}{
+		{in: "HTTP://Example.com:80/a/../b?", out: "http://example.com/b", flags: SafeNormalization},
+		{in: "http://example.com/b", out: "http://example.com/b", flags: SafeNormalization},
+		{in: "https://example.com:443", out: "https://example.com/", flags: SafeNormalization},
+		{in: "https://example.com:8443/", out: "https://example.com:8443/", flags: SafeNormalization},
+		{in: "http://example.com:/a/./b/../../c/d", out: "http://example.com/c/d", flags: SafeNormalization},
+		{in: "http://example.com/%7euser/%2f%3a", out: "http://example.com/~user/%2F%3A", flags: SafeNormalization},
+		{in: "http://example.com/?b=2&a=1&b=1#top", out: "http://example.com/?b=2&a=1&b=1#top", flags: SafeNormalization},
+		{in: "http://example.com/?b=2&a=1&b=1#top", out: "http://example.com/?a=1&b=2&b=1", flags: AggressiveNormalization},
+		{in: "HTTP://Example.com:80/a/../b?", out: "http://example.com:80/a/../b?", flags: NormalizeCase},
+		{in: "mailto:A@Example.com", out: "mailto:A@Example.com", flags: SafeNormalization},
+		{in: "/a/../b", out: "/b", flags: SafeNormalization},
+		{in: "a/../b", out: "a/../b", flags: SafeNormalization},
+		{in: "../a", out: "../a", flags: SafeNormalization},
+	}
+	for _, test := range tests {
+		out, err := NormalizeURL(test.in, test.flags)
+		if err != nil {
+			t.Errorf("NormalizeURL(%q) should not error: %s", test.in, err)
+			continue
+		}
+		if out != test.out {
+			t.Errorf("unexpected normalized URL: %s != %s", out, test.out)
+		}
+	}
+
+	// Normalize the URL in place
+	url := URL("HTTP://Example.com:80/a/../b?")
+	if err := url.Normalize(); err != nil {
+		t.Fatalf("URL normalization should not fail: %s", err)
+	}
+	if url != "http://example.com/b" {
+		t.Errorf("unexpected URL: %s != http://example.com/b", url)
+	}
+	url = URL("http://example.com/?b=1&a=2#frag")
+	if err := url.Normalize(SortQuery, StripFragment); err != nil {
+		t.Fatalf("URL normalization should not fail: %s", err)
+	}
+	if url != "http://example.com/?a=2&b=1" {
+		t.Errorf("unexpected URL: %s != http://example.com/?a=2&b=1", url)
+	}
+
+	invalid := URL("%zz")
+	if err := invalid.Normalize(); err == nil {
+		t.Errorf("URL normalization should fail with an invalid URL")
+	}
+}
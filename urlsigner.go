@@ -0,0 +1,190 @@
+package fields
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	neturl "net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Query parameters added to signed URLs
+const (
+	SignedURLExpires   = "expires"
+	SignedURLKeyID     = "kid"
+	SignedURLSignature = "signature"
+)
+
+// Errors returned by URLSigner.Verify
+var (
+	ErrURLExpired      = errors.New("Signed URL has expired")
+	ErrURLBadSignature = errors.New("Signed URL has an invalid signature")
+	ErrURLMalformed    = errors.New("Signed URL is malformed")
+)
+
+// URLSigner signs URLs with an expiry and an HMAC-SHA256 signature. Keys
+// are identified by a key ID, which is included in the signed URL, so that
+// keys can be rotated: new URLs are signed with the current key, while
+// URLs signed with older keys still verify until those keys are removed.
+// It is safe for concurrent use, including rotation while URLs are being
+// signed and verified. The zero value verifies no URLs and cannot sign
+// until a key is set with Rotate.
+type URLSigner struct {
+	mu      sync.RWMutex
+	current string
+	keys    map[string][]byte
+	now     func() time.Time
+}
+
+// setKey adds the key. The lock must be held.
+func (signer *URLSigner) setKey(keyID string, key []byte) error {
+	if len(key) < sha256.Size {
+		return fmt.Errorf(
+			"URL signing keys must be at least %d bytes", sha256.Size,
+		)
+	}
+	if signer.keys == nil {
+		signer.keys = make(map[string][]byte)
+	}
+	signer.keys[keyID] = append([]byte(nil), key...)
+	return nil
+}
+
+// AddKey adds a key that is used to verify, but not sign, URLs. Keys must
+// be at least 32 bytes.
+func (signer *URLSigner) AddKey(keyID string, key []byte) error {
+	signer.mu.Lock()
+	defer signer.mu.Unlock()
+	return signer.setKey(keyID, key)
+}
+
+// RemoveKey removes a verification key. The current signing key cannot be
+// removed.
+func (signer *URLSigner) RemoveKey(keyID string) *URLSigner {
+	signer.mu.Lock()
+	defer signer.mu.Unlock()
+	if keyID != signer.current {
+		delete(signer.keys, keyID)
+	}
+	return signer
+}
+
+// Rotate makes the given key the current signing key. The previous key
+// still verifies URLs until it is removed. Keys must be at least 32 bytes.
+func (signer *URLSigner) Rotate(keyID string, key []byte) error {
+	signer.mu.Lock()
+	defer signer.mu.Unlock()
+	if err := signer.setKey(keyID, key); err != nil {
+		return err
+	}
+	signer.current = keyID
+	return nil
+}
+
+// Sign returns the URL with its expiry, key ID and signature added as
+// query parameters. Any fragment is removed.
+func (signer *URLSigner) Sign(url URL, expires time.Time) (URL, error) {
+	parsed, err := ParseURL(string(url), RequireAbsolute(), RequireHost())
+	if err != nil {
+		return "", err
+	}
+	query, err := neturl.ParseQuery(parsed.RawQuery)
+	if err != nil {
+		return "", URLError{Rule: RuleURLSyntax, Reason: err.Error()}
+	}
+	for _, param := range []string{SignedURLExpires, SignedURLKeyID, SignedURLSignature} {
+		if _, exists := query[param]; exists {
+			return "", fmt.Errorf(
+				"URLs to be signed cannot have a '%s' parameter", param,
+			)
+		}
+	}
+	signer.mu.RLock()
+	keyID, key := signer.current, signer.keys[signer.current]
+	signer.mu.RUnlock()
+	if key == nil {
+		return "", fmt.Errorf("URL signers must have a signing key - use Rotate")
+	}
+
+	query.Set(SignedURLExpires, strconv.FormatInt(expires.Unix(), 10))
+	query.Set(SignedURLKeyID, keyID)
+	parsed.RawQuery = query.Encode()
+	parsed.Fragment, parsed.RawFragment = "", ""
+
+	signature := signer.sign(key, parsed, query)
+	parsed.RawQuery += "&" + SignedURLSignature + "=" + signature
+	return URL(parsed.String()), nil
+}
+
+// Verify returns nil if the URL was signed by one of the signer's keys and
+// has not expired. Otherwise ErrURLMalformed, ErrURLBadSignature or
+// ErrURLExpired is returned. The order of query parameters is ignored.
+func (signer *URLSigner) Verify(url URL) error {
+	parsed, err := neturl.Parse(strings.TrimSpace(string(url)))
+	if err != nil {
+		return ErrURLMalformed
+	}
+	query, err := neturl.ParseQuery(parsed.RawQuery)
+	if err != nil {
+		return ErrURLMalformed
+	}
+	for _, param := range []string{SignedURLExpires, SignedURLKeyID, SignedURLSignature} {
+		if len(query[param]) != 1 {
+			return ErrURLMalformed
+		}
+	}
+	expires, err := strconv.ParseInt(query.Get(SignedURLExpires), 10, 64)
+	if err != nil {
+		return ErrURLMalformed
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(query.Get(SignedURLSignature))
+	if err != nil {
+		return ErrURLMalformed
+	}
+
+	signer.mu.RLock()
+	key, ok := signer.keys[query.Get(SignedURLKeyID)]
+	signer.mu.RUnlock()
+	if !ok {
+		return ErrURLBadSignature
+	}
+	query.Del(SignedURLSignature)
+	expected, _ := base64.RawURLEncoding.DecodeString(signer.sign(key, parsed, query))
+	if !hmac.Equal(signature, expected) {
+		return ErrURLBadSignature
+	}
+	// Only authentic URLs are reported as expired
+	now := time.Now
+	if signer.now != nil {
+		now = signer.now
+	}
+	if !now().Before(time.Unix(expires, 0)) {
+		return ErrURLExpired
+	}
+	return nil
+}
+
+// sign returns the signature of the URL's scheme, host, path and query.
+// Query parameters are sorted by key, so their order does not matter.
+func (signer *URLSigner) sign(key []byte, url *neturl.URL, query neturl.Values) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(strings.ToLower(url.Scheme) + "://" + strings.ToLower(url.Host)))
+	mac.Write([]byte(url.EscapedPath()))
+	mac.Write([]byte("?" + query.Encode()))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// NewURLSigner creates a new URLSigner that signs URLs with the given key.
+// Keys must be at least 32 bytes.
+func NewURLSigner(keyID string, key []byte) (*URLSigner, error) {
+	signer := &URLSigner{now: time.Now}
+	if err := signer.Rotate(keyID, key); err != nil {
+		return nil, err
+	}
+	return signer, nil
+}
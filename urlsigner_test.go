@@ -0,0 +1,176 @@
+package fields
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestURLSigner(t *testing.T) {
+	if _, err := NewURLSigner("a", []byte("short")); err == nil {
+		t.Errorf("NewURLSigner should error with a short key")
+	}
+
+	now := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	signer, err := NewURLSigner("2016", bytes.Repeat([]byte{1}, 32))
+	if err != nil {
+		t.Fatalf("NewURLSigner should not error: %s", err)
+	}
+	signer.now = func() time.Time { return now }
+
+	signed, err := signer.Sign("https://example.com/files/a.pdf?b=2&a=1#page", now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Sign should not error: %s", err)
+	}
+	if strings.Contains(string(signed), "#page") {
+		t.Errorf("Signed URLs should not have a fragment: %s", signed)
+	}
+	if err := signer.Verify(signed); err != nil {
+		t.Errorf("Verify should not error: %s", err)
+	}
+
+	// Parameter order is ignored
+	parsed := signed.Parsed()
+	params := strings.Split(parsed.RawQuery, "&")
+	for i, j := 0, len(params)-1; i < j; i, j = i+1, j-1 {
+		params[i], params[j] = params[j], params[i]
+	}
+	parsed.RawQuery = strings.Join(params, "&")
+	if err := signer.Verify(URL(parsed.String())); err != nil {
+		t.Errorf("Verify should not error with reordered parameters: %s", err)
+	}
+
+	// Tampering with the URL invalidates the signature
+	tampered := URL(strings.Replace(string(signed), "a=1", "a=2", 1))
+	if err := signer.Verify(tampered); err != ErrURLBadSignature {
+		t.Errorf("unexpected error: %v != %s", err, ErrURLBadSignature)
+	}
+	tampered = URL(strings.Replace(string(signed), "a.pdf", "b.pdf", 1))
+	if err := signer.Verify(tampered); err != ErrURLBadSignature {
+		t.Errorf("unexpected error: %v != %s", err, ErrURLBadSignature)
+	}
+
+	malformed := []URL{
+		"https://example.com/files/a.pdf",
+		URL(strings.Replace(string(signed), "expires=", "expires=x", 1)),
+		URL(string(signed) + "&signature=again"),
+	}
+	for _, url := range malformed {
+		if err := signer.Verify(url); err != ErrURLMalformed {
+			t.Errorf("unexpected error for %s: %v != %s", url, err, ErrURLMalformed)
+		}
+	}
+
+	now = now.Add(time.Hour)
+	if err := signer.Verify(signed); err != ErrURLExpired {
+		t.Errorf("unexpected error: %v != %s", err, ErrURLExpired)
+	}
+
+	if _, err := signer.Sign("https://example.com/?kid=1", now); err == nil {
+		t.Errorf("Sign should error when the URL has a reserved parameter")
+	}
+}
+
+func TestURLSigner_Rotation(t *testing.T) {
+	now := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	oldKey := bytes.Repeat([]byte{1}, 32)
+	old, _ := NewURLSigner("old", oldKey)
+	signed, err := old.Sign("https://example.com/a", now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Sign should not error: %s", err)
+	}
+
+	signer, _ := NewURLSigner("new", bytes.Repeat([]byte{2}, 32))
+	signer.now = func() time.Time { return now }
+	if err := signer.Verify(signed); err != ErrURLBadSignature {
+		t.Errorf("unexpected error with an unknown key: %v", err)
+	}
+	if err := signer.AddKey("short", []byte("short")); err == nil {
+		t.Errorf("AddKey should error with a short key")
+	}
+	if err := signer.AddKey("old", oldKey); err != nil {
+		t.Errorf("AddKey should not error: %s", err)
+	}
+	if err := signer.Verify(signed); err != nil {
+		t.Errorf("Verify should not error with a previous key: %s", err)
+	}
+
+	renewed, _ := signer.Sign("https://example.com/a", now.Add(time.Hour))
+	if !strings.Contains(string(renewed), "kid=new") {
+		t.Errorf("URLs should be signed with the current key: %s", renewed)
+	}
+
+	signer.RemoveKey("old").RemoveKey("new")
+	if err := signer.Verify(signed); err != ErrURLBadSignature {
+		t.Errorf("unexpected error with a removed key: %v", err)
+	}
+	if err := signer.Verify(renewed); err != nil {
+		t.Errorf("The current key should not be removed: %s", err)
+	}
+}
+
+func TestURLSigner_Rotate(t *testing.T) {
+	now := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	signer, _ := NewURLSigner("2016", bytes.Repeat([]byte{1}, 32))
+	signer.now = func() time.Time { return now }
+	signed, _ := signer.Sign("https://example.com/a", now.Add(time.Hour))
+
+	if err := signer.Rotate("2017", []byte("short")); err == nil {
+		t.Errorf("Rotate should error with a short key")
+	}
+
+	// Rotate while URLs are signed and verified, which fails under -race
+	// without locking
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			keyID := fmt.Sprintf("2017-%d", i)
+			signer.Rotate(keyID, bytes.Repeat([]byte{byte(i + 2)}, 32))
+			signer.RemoveKey(keyID)
+		}(i)
+		go func() {
+			defer wg.Done()
+			if err := signer.Verify(signed); err != nil {
+				t.Errorf("Verify should not error during rotation: %s", err)
+			}
+			signer.Sign("https://example.com/b", now.Add(time.Hour))
+		}()
+	}
+	wg.Wait()
+
+	if err := signer.Rotate("2018", bytes.Repeat([]byte{9}, 32)); err != nil {
+		t.Fatalf("Rotate should not error: %s", err)
+	}
+	renewed, _ := signer.Sign("https://example.com/a", now.Add(time.Hour))
+	if !strings.Contains(string(renewed), "kid=2018") {
+		t.Errorf("URLs should be signed with the rotated key: %s", renewed)
+	}
+	if err := signer.Verify(signed); err != nil {
+		t.Errorf("Verify should not error with the previous key: %s", err)
+	}
+}
+
+func TestURLSigner_Zero(t *testing.T) {
+	var signer URLSigner
+	if err := signer.Verify("https://example.com/a?expires=1&kid=a&signature=AA"); err != ErrURLBadSignature {
+		t.Errorf("unexpected error with the zero value: %v", err)
+	}
+	if _, err := signer.Sign("https://example.com/a", time.Now().Add(time.Hour)); err == nil {
+		t.Errorf("Sign should error without a signing key")
+	}
+	if err := signer.Rotate("a", bytes.Repeat([]byte{1}, 32)); err != nil {
+		t.Fatalf("Rotate should not error: %s", err)
+	}
+	signed, err := signer.Sign("https://example.com/a", time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Sign should not error: %s", err)
+	}
+	if err := signer.Verify(signed); err != nil {
+		t.Errorf("Verify should not error: %s", err)
+	}
+}
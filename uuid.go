@@ -1,20 +1,19 @@
 package fields
 
 import (
-	"crypto/rand"
 	"database/sql/driver"
 	"fmt"
-	"io"
-	"log"
+	"strings"
 
 	"github.com/aodin/sol"
 	"github.com/aodin/sol/postgres"
+	"github.com/aodin/sol/types"
 )
 
 // Copyright 2011 Google Inc.  All rights reserved.
 // Use of this source code is governed by a BSD-style
 // UUID code is a variant of code.google.com/p/go-uuid
-// Added database driver and restriction to v4
+// Added database driver and RFC 9562 versions
 
 var UUIDv4 = postgres.UUID().NotNull()
 
@@ -43,10 +42,10 @@ func (u *UUID) UnmarshalJSON(data []byte) error {
 		return nil
 	}
 	if len(data) < 2 || data[0] != '"' || data[len(data)-1] != '"' {
-		return fmt.Errorf("UUIDs must be a valid UUID version 4")
+		return fmt.Errorf("UUIDs must be a JSON string")
 	}
 	data = data[1 : len(data)-1]
-	uu, err := ParseUUID(string(data))
+	uu, err := ParseLenientUUID(string(data))
 	if err != nil {
 		return err
 	}
@@ -54,9 +53,66 @@ func (u *UUID) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-// Scan converts an SQL value into a UUID
+// MarshalText implements the encoding.TextMarshaler interface. It returns
+// the canonical form of the UUID.
+func (uuid UUID) MarshalText() ([]byte, error) {
+	return []byte(uuid.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface. It
+// accepts any of the formats of ParseLenientUUID. Empty text is ignored.
+func (u *UUID) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		return nil
+	}
+	uu, err := ParseLenientUUID(string(text))
+	if err != nil {
+		return err
+	}
+	*u = uu
+	return nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface. It
+// returns the 16 bytes of the UUID.
+func (uuid UUID) MarshalBinary() ([]byte, error) {
+	return uuid[:], nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface. It
+// requires exactly 16 bytes.
+func (uuid *UUID) UnmarshalBinary(data []byte) error {
+	if len(data) != 16 {
+		return fmt.Errorf("Binary UUIDs must have a length of 16 bytes")
+	}
+	copy(uuid[:], data)
+	return nil
+}
+
+// Scan converts an SQL value into a UUID. It accepts strings in any of the
+// formats of ParseLenientUUID, as either a string or bytes, and 16 byte
+// binary values, such as those of bytea or binary(16) columns. NULL values
+// will error - use NullUUID for nullable columns.
 func (uuid *UUID) Scan(value interface{}) error {
-	uu, _ := ParseUUID(string(value.([]byte)))
+	var uu UUID
+	var err error
+	switch v := value.(type) {
+	case string:
+		uu, err = ParseLenientUUID(v)
+	case []byte:
+		if len(v) == 16 {
+			err = uu.UnmarshalBinary(v)
+		} else {
+			uu, err = ParseLenientUUID(string(v))
+		}
+	case nil:
+		return fmt.Errorf("UUID scan returned NULL - use NullUUID")
+	default:
+		return fmt.Errorf("UUID scan returned unsupported type %T", value)
+	}
+	if err != nil {
+		return err
+	}
 	*uuid = uu
 	return nil
 }
@@ -71,9 +127,10 @@ func (uuid UUID) Equals(other UUID) bool {
 	return uuid == other
 }
 
-// Exists returns true if the UUID is a valid UUID
+// Exists returns true if the UUID is a valid UUID of a supported version.
+// See Validate.
 func (uuid UUID) Exists() bool {
-	return uuid[6] >= 0x40 && uuid[6] < 0x50
+	return uuid.Validate() == nil
 }
 
 func (uuid UUID) Keys() []interface{} {
@@ -86,17 +143,14 @@ func (uuid UUID) Modify(table sol.Tabular) error {
 	return sol.Column("uuid", UUIDv4).Modify(table)
 }
 
+// NewUUID creates a random version 4 UUID
 // http://en.wikipedia.org/wiki/Universally_unique_identifier#Version_4_.28random.29
-func NewUUID() (u UUID) {
-	if _, err := io.ReadFull(rand.Reader, u[:]); err != nil {
-		log.Panic(err) // rand should never fail
-	}
-	u[6] = (u[6] & 0x0f) | 0x40 // Version 4
-	u[8] = (u[8] & 0x3f) | 0x80 // Variant is 10
-	return
+func NewUUID() UUID {
+	return mustUUID(DefaultUUIDGenerator().NewV4())
 }
 
-// TODO require v4
+// ParseUUID parses a UUID of any version. Use Validate to check the
+// version and variant.
 func ParseUUID(s string) (UUID, error) {
 	if len(s) != 36 {
 		return UUID{}, fmt.Errorf("UUIDs must have a length of 36 characters")
@@ -125,6 +179,31 @@ func ParseUUID(s string) (UUID, error) {
 	return uuid, nil
 }
 
+// ParseLenientUUID parses a UUID in the canonical form or any of the
+// following: surrounded by braces, with a urn:uuid: prefix, or as 32 hex
+// characters without dashes. Hex characters can be of either case.
+func ParseLenientUUID(s string) (UUID, error) {
+	s = strings.TrimSpace(s)
+	if len(s) > 9 && strings.EqualFold(s[:9], "urn:uuid:") {
+		s = s[9:]
+	} else if len(s) > 2 && s[0] == '{' && s[len(s)-1] == '}' {
+		s = s[1 : len(s)-1]
+	}
+	if len(s) != 32 {
+		return ParseUUID(s)
+	}
+	var uuid UUID
+	for i := range uuid {
+		v, ok := xtob(s[i*2:])
+		if !ok {
+			return UUID{}, fmt.Errorf(
+				"UUIDs must have a valid hex encoded byte starting at position %d", i*2)
+		}
+		uuid[i] = v
+	}
+	return uuid, nil
+}
+
 // xvalues returns the value of a byte as a hexadecimal digit or 255.
 var xvalues = []byte{
 	255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255, 255,
@@ -151,3 +230,47 @@ func xtob(x string) (byte, bool) {
 	b2 := xvalues[x[1]]
 	return (b1 << 4) | b2, b1 != 255 && b2 != 255
 }
+
+// UUIDColumnElem is a Modifier for a UUID column. It is created with
+// UUIDColumn.
+type UUIDColumnElem struct {
+	name    string
+	options columnOptions
+}
+
+var _ sol.Modifier = UUIDColumnElem{}
+
+// Type returns the column's type and constraints
+func (column UUIDColumnElem) Type() types.Type {
+	return columnType{
+		err:         column.validate(),
+		name:        "UUID",
+		notNull:     column.options.notNull,
+		defaultExpr: column.options.defaultExpr,
+		primaryKey:  column.options.primaryKey,
+		unique:      column.options.unique,
+	}
+}
+
+func (column UUIDColumnElem) validate() error {
+	return column.options.supports(
+		"UUID", "NotNull", "Unique", "PrimaryKey", "Default",
+	)
+}
+
+// Modify implements the sol.Modifier interface. It will error if given an
+// unsupported option.
+func (column UUIDColumnElem) Modify(table sol.Tabular) error {
+	if err := column.validate(); err != nil {
+		return err
+	}
+	return sol.Column(column.name, column.Type()).Modify(table)
+}
+
+// UUIDColumn returns a Modifier for a UUID column with the given name. It
+// accepts the NotNull, Unique, PrimaryKey and Default options, such as
+// UUIDColumn("id", PrimaryKey(), Default("gen_random_uuid()")). The
+// Modify method of UUID remains a NOT NULL column named uuid.
+func UUIDColumn(name string, opts ...ColumnOption) UUIDColumnElem {
+	return UUIDColumnElem{name: name, options: newColumnOptions(opts)}
+}
@@ -2,8 +2,16 @@ package fields
 
 import (
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"testing"
+
+	sql "github.com/aodin/sol"
+)
+
+var UUIDTests = sql.Table("uuid_tests",
+	UUIDColumn("id", PrimaryKey(), Default("gen_random_uuid()")),
+	UUIDColumn("public_id", NotNull(), Unique()),
 )
 
 func TestUUID(t *testing.T) {
@@ -30,3 +38,166 @@ func TestUUID(t *testing.T) {
 		t.Errorf("Execpected JSON marshal output: %s != %s", out, string(b))
 	}
 }
+
+func TestParseLenientUUID(t *testing.T) {
+	canonical := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	for _, s := range []string{
+		canonical,
+		"6BA7B810-9DAD-11D1-80B4-00C04FD430C8",
+		"{6ba7b810-9dad-11d1-80b4-00c04fd430c8}",
+		"urn:uuid:6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+		"URN:UUID:6BA7B810-9DAD-11D1-80B4-00C04FD430C8",
+		"6ba7b8109dad11d180b400c04fd430c8",
+		" {6BA7B8109DAD11D180B400C04FD430C8} ",
+	} {
+		uuid, err := ParseLenientUUID(s)
+		if err != nil {
+			t.Errorf("ParseLenientUUID(%q) should not error: %s", s, err)
+			continue
+		}
+		if uuid.String() != canonical {
+			t.Errorf("unexpected UUID: %s != %s", uuid, canonical)
+		}
+	}
+
+	for _, s := range []string{
+		"", "{}", "urn:uuid:", "6ba7b8109dad11d180b400c04fd430cz",
+		"{6ba7b810-9dad-11d1-80b4-00c04fd430c8", "6ba7b810-9dad-11d1-80b4",
+	} {
+		if _, err := ParseLenientUUID(s); err == nil {
+			t.Errorf("ParseLenientUUID(%q) should error", s)
+		}
+	}
+
+	// The strict parser only accepts the canonical form
+	if _, err := ParseUUID("{" + canonical + "}"); err == nil {
+		t.Errorf("ParseUUID should error with braces")
+	}
+}
+
+func TestUUID_Text(t *testing.T) {
+	uuid := NewUUID()
+	m := map[UUID]int{uuid: 1}
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("JSON marshal should not error: %s", err)
+	}
+	if out := fmt.Sprintf(`{"%s":1}`, uuid); out != string(b) {
+		t.Errorf("unexpected JSON: %s != %s", b, out)
+	}
+	var decoded map[UUID]int
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatalf("JSON unmarshal should not error: %s", err)
+	}
+	if decoded[uuid] != 1 {
+		t.Errorf("unexpected map: %v", decoded)
+	}
+
+	type item struct {
+		ID UUID `xml:"id"`
+	}
+	xmlTest := item{ID: uuid}
+	out, err := xml.Marshal(xmlTest)
+	if err != nil {
+		t.Fatalf("XML marshal should not error: %s", err)
+	}
+	xmlTest.ID = UUID{}
+	if err := xml.Unmarshal(out, &xmlTest); err != nil {
+		t.Fatalf("XML unmarshal should not error: %s", err)
+	}
+	if xmlTest.ID != uuid {
+		t.Errorf("unexpected UUID: %s != %s", xmlTest.ID, uuid)
+	}
+
+	var parsed UUID
+	if err := parsed.UnmarshalText([]byte("urn:uuid:" + uuid.String())); err != nil {
+		t.Errorf("Unmarshal text should not error: %s", err)
+	}
+	if parsed != uuid {
+		t.Errorf("unexpected UUID: %s != %s", parsed, uuid)
+	}
+	if err := json.Unmarshal([]byte(`"{`+uuid.String()+`}"`), &parsed); err != nil {
+		t.Errorf("JSON unmarshal should accept braces: %s", err)
+	}
+}
+
+func TestUUID_Scan(t *testing.T) {
+	id := NewUUID()
+	for _, value := range []interface{}{
+		id.String(),
+		[]byte(id.String()),
+		id[:],
+	} {
+		var scanned UUID
+		if err := scanned.Scan(value); err != nil {
+			t.Errorf("Scan should not error with %T: %s", value, err)
+		}
+		if scanned != id {
+			t.Errorf("unexpected scanned UUID: %s != %s", scanned, id)
+		}
+	}
+
+	// Corrupt values should error rather than become the zero UUID
+	for _, value := range []interface{}{
+		"not-a-uuid", []byte("not-a-uuid"), []byte{1, 2, 3}, nil, 1,
+	} {
+		scanned := id
+		if err := scanned.Scan(value); err == nil {
+			t.Errorf("Scan should error with %v", value)
+		}
+		if scanned != id {
+			t.Errorf("UUID should be unchanged after an error")
+		}
+	}
+
+	b, err := id.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary should not error: %s", err)
+	}
+	var decoded UUID
+	if err := decoded.UnmarshalBinary(b); err != nil {
+		t.Fatalf("UnmarshalBinary should not error: %s", err)
+	}
+	if decoded != id {
+		t.Errorf("unexpected UUID: %s != %s", decoded, id)
+	}
+	if err := decoded.UnmarshalBinary(b[:15]); err == nil {
+		t.Errorf("UnmarshalBinary should error without 16 bytes")
+	}
+}
+
+func TestUUIDColumn(t *testing.T) {
+	tests := []struct {
+		column UUIDColumnElem
+		out    string
+	}{
+		{column: UUIDColumn("uuid"), out: "UUID"},
+		{
+			column: UUIDColumn("id", PrimaryKey(), Default("gen_random_uuid()")),
+			out:    "UUID DEFAULT gen_random_uuid() PRIMARY KEY",
+		},
+		{
+			column: UUIDColumn("public_id", NotNull(), Unique()),
+			out:    "UUID NOT NULL UNIQUE",
+		},
+	}
+	for _, test := range tests {
+		out, err := test.column.Type().Create(nil)
+		if err != nil {
+			t.Errorf("Create should not error: %s", err)
+		}
+		if out != test.out {
+			t.Errorf("unexpected column type: %s != %s", out, test.out)
+		}
+	}
+}
+
+func TestUUIDColumn_Unsupported(t *testing.T) {
+	column := UUIDColumn("id", Check())
+	if err := column.Modify(sql.Table("unsupported")); err == nil {
+		t.Errorf("Modify should error with an unsupported option")
+	}
+	if _, err := column.Type().Create(nil); err == nil {
+		t.Errorf("Create should error with an unsupported option")
+	}
+}
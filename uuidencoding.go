@@ -0,0 +1,194 @@
+package fields
+
+import (
+	"database/sql/driver"
+	"encoding/base64"
+	"fmt"
+)
+
+// Alphabets of the compact UUID encodings
+const (
+	base58Alphabet    = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+)
+
+// Lengths of the compact UUID encodings
+const (
+	base58Length    = 22
+	base32Length    = 26
+	base64URLLength = 22
+)
+
+var base58Values, crockfordValues = decodeTable(base58Alphabet), crockfordTable()
+
+// decodeTable returns the value of each byte in the alphabet or 255
+func decodeTable(alphabet string) (table [256]byte) {
+	for i := range table {
+		table[i] = 255
+	}
+	for i := 0; i < len(alphabet); i++ {
+		table[alphabet[i]] = byte(i)
+	}
+	return
+}
+
+// crockfordTable decodes Crockford's base32 without regard to case, with
+// I and L read as 1 and O read as 0
+func crockfordTable() [256]byte {
+	table := decodeTable(crockfordAlphabet)
+	for i := 0; i < len(crockfordAlphabet); i++ {
+		c := crockfordAlphabet[i]
+		if c >= 'A' && c <= 'Z' {
+			table[c+'a'-'A'] = byte(i)
+		}
+	}
+	table['I'], table['i'], table['L'], table['l'] = 1, 1, 1, 1
+	table['O'], table['o'] = 0, 0
+	return table
+}
+
+// encodeBase converts the 128 bit big-endian number into the given
+// alphabet, padded with the alphabet's zero digit to the given width
+func encodeBase(u [16]byte, alphabet string, width int) string {
+	base := uint(len(alphabet))
+	out := make([]byte, width)
+	for i := width - 1; i >= 0; i-- {
+		var rem uint
+		for j := range u {
+			acc := rem<<8 | uint(u[j])
+			u[j] = byte(acc / base)
+			rem = acc % base
+		}
+		out[i] = alphabet[rem]
+	}
+	return string(out)
+}
+
+// decodeBase converts the string into a 128 bit big-endian number. The
+// name, such as "ULIDs", is used in errors.
+func decodeBase(s string, values *[256]byte, base uint, width int, name string) (u [16]byte, err error) {
+	if len(s) != width {
+		return [16]byte{}, fmt.Errorf(
+			"%s must have a length of %d characters", name, width,
+		)
+	}
+	for i := 0; i < len(s); i++ {
+		carry := uint(values[s[i]])
+		if carry == 255 {
+			return [16]byte{}, fmt.Errorf(
+				"%s have an invalid character at position %d", name, i,
+			)
+		}
+		for j := len(u) - 1; j >= 0; j-- {
+			acc := uint(u[j])*base + carry
+			u[j] = byte(acc)
+			carry = acc >> 8
+		}
+		if carry != 0 {
+			return [16]byte{}, fmt.Errorf("%s cannot exceed 128 bits", name)
+		}
+	}
+	return u, nil
+}
+
+// Base58 returns the UUID as 22 characters of the Bitcoin base58 alphabet
+func (uuid UUID) Base58() string {
+	return encodeBase(uuid, base58Alphabet, base58Length)
+}
+
+// Base32 returns the UUID as 26 characters of Crockford's base32 alphabet
+func (uuid UUID) Base32() string {
+	return encodeBase(uuid, crockfordAlphabet, base32Length)
+}
+
+// Base64URL returns the UUID as 22 characters of unpadded URL-safe base64
+func (uuid UUID) Base64URL() string {
+	return base64.RawURLEncoding.EncodeToString(uuid[:])
+}
+
+// ParseUUIDBase58 parses a UUID created by Base58
+func ParseUUIDBase58(s string) (UUID, error) {
+	return decodeBase(s, &base58Values, 58, base58Length, "Base58 UUIDs")
+}
+
+// ParseUUIDBase32 parses a UUID created by Base32. Decoding ignores case
+// and reads I and L as 1 and O as 0.
+func ParseUUIDBase32(s string) (UUID, error) {
+	return decodeBase(s, &crockfordValues, 32, base32Length, "Base32 UUIDs")
+}
+
+// ParseUUIDBase64URL parses a UUID created by Base64URL
+func ParseUUIDBase64URL(s string) (UUID, error) {
+	if len(s) != base64URLLength {
+		return UUID{}, fmt.Errorf(
+			"Base64URL UUIDs must have a length of %d characters", base64URLLength,
+		)
+	}
+	var uuid UUID
+	b, err := base64.RawURLEncoding.Strict().DecodeString(s)
+	if err != nil {
+		return UUID{}, fmt.Errorf("Base64URL UUIDs must be valid base64: %s", err)
+	}
+	copy(uuid[:], b)
+	return uuid, nil
+}
+
+// ShortUUID is a UUID whose JSON and text forms use the base58 encoding,
+// such as in public URLs. It is stored in the database as a canonical
+// UUID.
+type ShortUUID UUID
+
+// UUID returns the ShortUUID as a UUID
+func (uuid ShortUUID) UUID() UUID {
+	return UUID(uuid)
+}
+
+// String returns the base58 form of the UUID
+func (uuid ShortUUID) String() string {
+	return UUID(uuid).Base58()
+}
+
+// MarshalText implements the encoding.TextMarshaler interface
+func (uuid ShortUUID) MarshalText() ([]byte, error) {
+	return []byte(uuid.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface. The
+// base58 form and any of the formats of ParseLenientUUID are accepted.
+func (uuid *ShortUUID) UnmarshalText(text []byte) error {
+	var uu UUID
+	var err error
+	if len(text) == base58Length {
+		uu, err = ParseUUIDBase58(string(text))
+	} else {
+		uu, err = ParseLenientUUID(string(text))
+	}
+	if err != nil {
+		return err
+	}
+	*uuid = ShortUUID(uu)
+	return nil
+}
+
+// MarshalJSON returns the base58 form of the UUID as a JSON string
+func (uuid ShortUUID) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + uuid.String() + `"`), nil
+}
+
+// UnmarshalJSON accepts the same formats as UnmarshalText
+func (uuid *ShortUUID) UnmarshalJSON(data []byte) error {
+	if len(data) < 2 || data[0] != '"' || data[len(data)-1] != '"' {
+		return fmt.Errorf("UUIDs must be a JSON string")
+	}
+	return uuid.UnmarshalText(data[1 : len(data)-1])
+}
+
+// Scan converts an SQL value into a ShortUUID
+func (uuid *ShortUUID) Scan(value interface{}) error {
+	return (*UUID)(uuid).Scan(value)
+}
+
+// Value returns the canonical form of the UUID for insert into SQL
+func (uuid ShortUUID) Value() (driver.Value, error) {
+	return UUID(uuid).Value()
+}
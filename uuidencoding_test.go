@@ -0,0 +1,130 @@
+package fields
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestUUID_Encodings(t *testing.T) {
+	max := UUID{
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+	}
+	known, _ := ParseUUID("017f22e2-79b0-7cc3-98c4-dc0c0c07398f")
+
+	tests := []struct {
+		uuid                      UUID
+		base58, base32, base64url string
+	}{
+		{
+			uuid:      UUID{},
+			base58:    "1111111111111111111111",
+			base32:    "00000000000000000000000000",
+			base64url: "AAAAAAAAAAAAAAAAAAAAAA",
+		},
+		{
+			uuid:      max,
+			base58:    "YcVfxkQb6JRzqk5kF2tNLv",
+			base32:    "7ZZZZZZZZZZZZZZZZZZZZZZZZZ",
+			base64url: "_____________________w",
+		},
+		{
+			uuid:      known,
+			base32:    "01FWHE4YDGFK1SHH6W1G60EECF",
+			base64url: "AX8i4nmwfMOYxNwMDAc5jw",
+		},
+	}
+	for _, test := range tests {
+		if test.base58 != "" && test.uuid.Base58() != test.base58 {
+			t.Errorf("unexpected base58: %s != %s", test.uuid.Base58(), test.base58)
+		}
+		if test.uuid.Base32() != test.base32 {
+			t.Errorf("unexpected base32: %s != %s", test.uuid.Base32(), test.base32)
+		}
+		if test.uuid.Base64URL() != test.base64url {
+			t.Errorf("unexpected base64url: %s != %s", test.uuid.Base64URL(), test.base64url)
+		}
+	}
+
+	for i := 0; i < 100; i++ {
+		uuid := NewUUID()
+		if parsed, err := ParseUUIDBase58(uuid.Base58()); err != nil || parsed != uuid {
+			t.Errorf("base58 should round trip: %s %v", uuid, err)
+		}
+		if parsed, err := ParseUUIDBase32(strings.ToLower(uuid.Base32())); err != nil || parsed != uuid {
+			t.Errorf("base32 should round trip: %s %v", uuid, err)
+		}
+		if parsed, err := ParseUUIDBase64URL(uuid.Base64URL()); err != nil || parsed != uuid {
+			t.Errorf("base64url should round trip: %s %v", uuid, err)
+		}
+	}
+
+	// Crockford's base32 reads I and L as 1 and O as 0
+	if parsed, err := ParseUUIDBase32("OIFWHE4YDGFKLSHH6W1G60EECF"); err != nil || parsed != known {
+		t.Errorf("unexpected base32 decoding: %s %v", parsed, err)
+	}
+
+	invalid := []struct {
+		parse func(string) (UUID, error)
+		in    string
+	}{
+		{parse: ParseUUIDBase58, in: "111111111111111111111"},
+		{parse: ParseUUIDBase58, in: "111111111111111111111O"},
+		{parse: ParseUUIDBase58, in: "zzzzzzzzzzzzzzzzzzzzzz"},
+		{parse: ParseUUIDBase32, in: "80000000000000000000000000"},
+		{parse: ParseUUIDBase32, in: "0000000000000000000000000U"},
+		{parse: ParseUUIDBase64URL, in: "AAAAAAAAAAAAAAAAAAAAA+"},
+		{parse: ParseUUIDBase64URL, in: "AAAAAAAAAAAAAAAAAAAAAB"},
+	}
+	for _, test := range invalid {
+		if _, err := test.parse(test.in); err == nil {
+			t.Errorf("parsing %q should error", test.in)
+		}
+	}
+}
+
+func TestShortUUID(t *testing.T) {
+	uuid := NewUUID()
+	test := struct {
+		ID ShortUUID `json:"id"`
+	}{ID: ShortUUID(uuid)}
+
+	b, err := json.Marshal(test)
+	if err != nil {
+		t.Fatalf("JSON marshal should not error: %s", err)
+	}
+	if string(b) != `{"id":"`+uuid.Base58()+`"}` {
+		t.Errorf("unexpected JSON: %s", b)
+	}
+
+	test.ID = ShortUUID{}
+	if err := json.Unmarshal(b, &test); err != nil {
+		t.Fatalf("JSON unmarshal should not error: %s", err)
+	}
+	if test.ID.UUID() != uuid {
+		t.Errorf("unexpected UUID: %s != %s", test.ID.UUID(), uuid)
+	}
+
+	// Canonical UUIDs are also accepted
+	var short ShortUUID
+	if err := short.UnmarshalText([]byte(uuid.String())); err != nil {
+		t.Errorf("Unmarshal text should not error: %s", err)
+	}
+	if short.UUID() != uuid {
+		t.Errorf("unexpected UUID: %s != %s", short.UUID(), uuid)
+	}
+
+	// The database stores the canonical form
+	value, _ := short.Value()
+	if value != uuid.String() {
+		t.Errorf("unexpected value: %v != %s", value, uuid)
+	}
+	var scanned ShortUUID
+	if err := scanned.Scan([]byte(uuid.String())); err != nil {
+		t.Errorf("Scan should not error: %s", err)
+	}
+	if scanned != short {
+		t.Errorf("unexpected scanned UUID: %s != %s", scanned, short)
+	}
+}
@@ -0,0 +1,159 @@
+package fields
+
+import (
+	"crypto/rand"
+	"io"
+	"log"
+	"sync"
+	"time"
+)
+
+// UUIDv7Method selects how a UUIDGenerator fills the 12 bit rand_a field
+// of version 7 UUIDs, following RFC 9562 section 6.2
+type UUIDv7Method int
+
+const (
+	// CounterMethod uses rand_a as a counter (method 1). The counter is
+	// seeded randomly each millisecond, with its most significant bit
+	// cleared to leave room for rollover.
+	CounterMethod UUIDv7Method = iota
+
+	// SubMillisecondMethod uses rand_a for the fraction of the current
+	// millisecond (method 3)
+	SubMillisecondMethod
+)
+
+// UUIDGeneratorOption configures a UUIDGenerator
+type UUIDGeneratorOption func(*UUIDGenerator)
+
+// UUIDClock sets the generator's clock. The default is time.Now.
+func UUIDClock(clock func() time.Time) UUIDGeneratorOption {
+	return func(gen *UUIDGenerator) {
+		gen.clock = clock
+	}
+}
+
+// UUIDEntropy sets the generator's source of random bits. The default is
+// crypto/rand.Reader. The reader does not need to be safe for concurrent
+// use.
+func UUIDEntropy(entropy io.Reader) UUIDGeneratorOption {
+	return func(gen *UUIDGenerator) {
+		gen.entropy = entropy
+	}
+}
+
+// UUIDv7Mode sets how the generator keeps version 7 UUIDs increasing
+// within the same millisecond. The default is CounterMethod.
+func UUIDv7Mode(method UUIDv7Method) UUIDGeneratorOption {
+	return func(gen *UUIDGenerator) {
+		gen.method = method
+	}
+}
+
+// UUIDGenerator creates UUIDs and ULIDs from a configurable clock and
+// entropy source. Version 7 UUIDs and ULIDs from the same generator are
+// strictly increasing, even within the same millisecond or if the clock
+// moves backwards. It is safe for concurrent use.
+type UUIDGenerator struct {
+	clock   func() time.Time
+	entropy io.Reader
+	method  UUIDv7Method
+
+	mu     sync.Mutex
+	lastMS uint64
+	lastA  uint16
+
+	lastULID   ULID
+	lastULIDMS uint64
+}
+
+// NewV4 creates a random version 4 UUID
+func (gen *UUIDGenerator) NewV4() (u UUID, err error) {
+	gen.mu.Lock()
+	defer gen.mu.Unlock()
+	if _, err = io.ReadFull(gen.entropy, u[:]); err != nil {
+		return UUID{}, err
+	}
+	setVersion(&u, 4)
+	return
+}
+
+// NewV7 creates a time-ordered version 7 UUID that is greater than all
+// previous version 7 UUIDs created by the generator
+func (gen *UUIDGenerator) NewV7() (u UUID, err error) {
+	gen.mu.Lock()
+	defer gen.mu.Unlock()
+	if _, err = io.ReadFull(gen.entropy, u[6:]); err != nil {
+		return UUID{}, err
+	}
+
+	now := gen.clock()
+	ms := uint64(now.UnixMilli())
+	var a uint16
+	switch gen.method {
+	case SubMillisecondMethod:
+		a = uint16(int64(now.Nanosecond()%1e6) * 4096 / 1e6)
+	default:
+		a = (uint16(u[6])<<8 | uint16(u[7])) & 0x07ff
+	}
+	if ms < gen.lastMS || (ms == gen.lastMS && (gen.method == CounterMethod || a <= gen.lastA)) {
+		ms, a = gen.lastMS, gen.lastA+1
+		if a > 0x0fff {
+			// The counter rolled over, so borrow the next millisecond
+			ms, a = ms+1, 0
+		}
+	}
+	gen.lastMS, gen.lastA = ms, a
+
+	u[0], u[1], u[2] = byte(ms>>40), byte(ms>>32), byte(ms>>24)
+	u[3], u[4], u[5] = byte(ms>>16), byte(ms>>8), byte(ms)
+	u[6], u[7] = byte(a>>8), byte(a)
+	setVersion(&u, 7)
+	return
+}
+
+// NewUUIDGenerator creates a new UUIDGenerator
+func NewUUIDGenerator(opts ...UUIDGeneratorOption) *UUIDGenerator {
+	gen := &UUIDGenerator{
+		clock:   time.Now,
+		entropy: rand.Reader,
+		method:  CounterMethod,
+	}
+	for _, opt := range opts {
+		opt(gen)
+	}
+	return gen
+}
+
+// defaultUUIDGenerator is used by NewUUID, NewUUIDv7 and NewULID
+var defaultUUIDGenerator = struct {
+	sync.RWMutex
+	gen *UUIDGenerator
+}{gen: NewUUIDGenerator()}
+
+// DefaultUUIDGenerator returns the generator used by NewUUID, NewUUIDv7
+// and NewULID
+func DefaultUUIDGenerator() *UUIDGenerator {
+	defaultUUIDGenerator.RLock()
+	defer defaultUUIDGenerator.RUnlock()
+	return defaultUUIDGenerator.gen
+}
+
+// SetDefaultUUIDGenerator replaces the generator used by NewUUID, NewUUIDv7
+// and NewULID, such as with a seeded generator in tests. It returns the
+// previous generator so that it can be restored.
+func SetDefaultUUIDGenerator(gen *UUIDGenerator) *UUIDGenerator {
+	defaultUUIDGenerator.Lock()
+	defer defaultUUIDGenerator.Unlock()
+	prev := defaultUUIDGenerator.gen
+	defaultUUIDGenerator.gen = gen
+	return prev
+}
+
+// mustUUID panics if the UUID could not be generated
+func mustUUID(u UUID, err error) UUID {
+	if err != nil {
+		log.Panic(err) // the default entropy of crypto/rand should never fail
+	}
+	return u
+}
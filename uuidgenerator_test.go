@@ -0,0 +1,155 @@
+package fields
+
+import (
+	"bytes"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestUUIDGenerator(t *testing.T) {
+	now := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	gen := NewUUIDGenerator(
+		UUIDClock(func() time.Time { return now }),
+		UUIDEntropy(rand.New(rand.NewSource(1))),
+	)
+
+	// UUIDs in the same millisecond should be strictly increasing
+	prev, err := gen.NewV7()
+	if err != nil {
+		t.Fatalf("NewV7 should not error: %s", err)
+	}
+	for i := 0; i < 5000; i++ {
+		next, err := gen.NewV7()
+		if err != nil {
+			t.Fatalf("NewV7 should not error: %s", err)
+		}
+		if bytes.Compare(prev[:], next[:]) >= 0 {
+			t.Fatalf("UUIDs should be strictly increasing: %s >= %s", prev, next)
+		}
+		if err := next.Validate(); err != nil {
+			t.Fatalf("Validate should not error: %s", err)
+		}
+		prev = next
+	}
+	// The counter rolled over into the next millisecond
+	if !prev.Time().After(now) {
+		t.Errorf("unexpected time after rollover: %s", prev.Time())
+	}
+
+	// A clock that moves backwards should not break the ordering
+	now = now.Add(-time.Hour)
+	next, _ := gen.NewV7()
+	if bytes.Compare(prev[:], next[:]) >= 0 {
+		t.Errorf("UUIDs should be strictly increasing: %s >= %s", prev, next)
+	}
+
+	// The same clock and entropy create the same UUIDs
+	a := NewUUIDGenerator(UUIDEntropy(rand.New(rand.NewSource(2))))
+	b := NewUUIDGenerator(UUIDEntropy(rand.New(rand.NewSource(2))))
+	x, _ := a.NewV4()
+	y, _ := b.NewV4()
+	if x != y || x.Version() != 4 {
+		t.Errorf("UUIDs should be deterministic: %s != %s", x, y)
+	}
+
+	failing := NewUUIDGenerator(UUIDEntropy(bytes.NewReader(nil)))
+	if _, err := failing.NewV7(); err == nil {
+		t.Errorf("NewV7 should error when entropy fails")
+	}
+	if _, err := failing.NewV4(); err == nil {
+		t.Errorf("NewV4 should error when entropy fails")
+	}
+}
+
+func TestUUIDGenerator_SubMillisecond(t *testing.T) {
+	now := time.Date(2016, 1, 1, 0, 0, 0, 500000, time.UTC)
+	gen := NewUUIDGenerator(
+		UUIDClock(func() time.Time { return now }),
+		UUIDv7Mode(SubMillisecondMethod),
+	)
+	a, _ := gen.NewV7()
+	if a[6]&0x0f != 0x08 || a[7] != 0x00 {
+		t.Errorf("rand_a should hold the fraction of the millisecond: %x", a[6:8])
+	}
+	b, _ := gen.NewV7()
+	if bytes.Compare(a[:], b[:]) >= 0 {
+		t.Errorf("UUIDs should be strictly increasing: %s >= %s", a, b)
+	}
+}
+
+func TestUUIDGenerator_Concurrent(t *testing.T) {
+	gen := NewUUIDGenerator()
+	var mu sync.Mutex
+	seen := make(map[UUID]bool)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 500; j++ {
+				u, _ := gen.NewV7()
+				mu.Lock()
+				seen[u] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if len(seen) != 4000 {
+		t.Errorf("unexpected number of unique UUIDs: %d != 4000", len(seen))
+	}
+}
+
+func TestUUID_Time(t *testing.T) {
+	// Values from RFC 9562 appendix A: Tuesday, February 22, 2022 2:22:22 PM GMT-05:00
+	expected := time.Date(2022, 2, 22, 19, 22, 22, 0, time.UTC)
+	for _, s := range []string{
+		"c232ab00-9414-11ec-b3c8-9f6bdeced846",
+		"1ec9414c-232a-6b00-b3c8-9f6bdeced846",
+		"017f22e2-79b0-7cc3-98c4-dc0c0c07398f",
+	} {
+		uuid, err := ParseUUID(s)
+		if err != nil {
+			t.Fatalf("ParseUUID should not error: %s", err)
+		}
+		if !uuid.Time().Equal(expected) {
+			t.Errorf("unexpected time for %s: %s != %s", s, uuid.Time(), expected)
+		}
+	}
+
+	if !NewUUID().Time().IsZero() {
+		t.Errorf("v4 UUIDs should not have a time")
+	}
+	if since := time.Since(NewUUIDv1().Time()); since < 0 || since > time.Minute {
+		t.Errorf("unexpected v1 time: %s", NewUUIDv1().Time())
+	}
+	if since := time.Since(NewUUIDv7().Time()); since < -time.Second || since > time.Minute {
+		t.Errorf("unexpected v7 time: %s", NewUUIDv7().Time())
+	}
+}
+
+func TestSetDefaultUUIDGenerator(t *testing.T) {
+	seeded := func() *UUIDGenerator {
+		return NewUUIDGenerator(
+			UUIDClock(func() time.Time { return time.Unix(1451606400, 0) }),
+			UUIDEntropy(rand.New(rand.NewSource(3))),
+		)
+	}
+
+	prev := SetDefaultUUIDGenerator(seeded())
+	a, b := NewUUID(), NewUUIDv7()
+	SetDefaultUUIDGenerator(seeded())
+	x, y := NewUUID(), NewUUIDv7()
+	if SetDefaultUUIDGenerator(prev) == prev {
+		t.Errorf("SetDefaultUUIDGenerator should return the replaced generator")
+	}
+
+	if a != x || b != y {
+		t.Errorf("the package constructors should use the default generator")
+	}
+	if NewUUID() == a {
+		t.Errorf("the previous generator should be restored")
+	}
+}
@@ -0,0 +1,198 @@
+package fields
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"io"
+	"log"
+	"sync"
+	"time"
+)
+
+// UUIDVariant is the layout of a UUID, encoded in its most significant
+// bits of octet 8
+type UUIDVariant int
+
+// Variants defined by RFC 9562 section 4.1
+const (
+	VariantNCS UUIDVariant = iota
+	VariantRFC9562
+	VariantMicrosoft
+	VariantFuture
+)
+
+// String returns the name of the variant
+func (variant UUIDVariant) String() string {
+	switch variant {
+	case VariantNCS:
+		return "NCS"
+	case VariantRFC9562:
+		return "RFC 9562"
+	case VariantMicrosoft:
+		return "Microsoft"
+	}
+	return "Future"
+}
+
+// Namespaces for name-based UUIDs from RFC 9562 section 6.6
+var (
+	NamespaceDNS  = UUID{0x6b, 0xa7, 0xb8, 0x10, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+	NamespaceURL  = UUID{0x6b, 0xa7, 0xb8, 0x11, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+	NamespaceOID  = UUID{0x6b, 0xa7, 0xb8, 0x12, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+	NamespaceX500 = UUID{0x6b, 0xa7, 0xb8, 0x14, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+)
+
+// Version returns the version of the UUID, such as 4 for random UUIDs
+func (uuid UUID) Version() int {
+	return int(uuid[6] >> 4)
+}
+
+// Variant returns the variant of the UUID
+func (uuid UUID) Variant() UUIDVariant {
+	switch {
+	case uuid[8]&0x80 == 0x00:
+		return VariantNCS
+	case uuid[8]&0xc0 == 0x80:
+		return VariantRFC9562
+	case uuid[8]&0xe0 == 0xc0:
+		return VariantMicrosoft
+	}
+	return VariantFuture
+}
+
+// Time returns the timestamp embedded in version 1, 6 and 7 UUIDs. The
+// zero time is returned for other versions.
+func (uuid UUID) Time() time.Time {
+	var timestamp uint64
+	switch uuid.Version() {
+	case 1:
+		timestamp = uint64(binary.BigEndian.Uint16(uuid[6:])&0x0fff)<<48 |
+			uint64(binary.BigEndian.Uint16(uuid[4:]))<<32 |
+			uint64(binary.BigEndian.Uint32(uuid[0:]))
+	case 6:
+		timestamp = binary.BigEndian.Uint64(uuid[0:])>>16<<12 |
+			uint64(binary.BigEndian.Uint16(uuid[6:])&0x0fff)
+	case 7:
+		ms := binary.BigEndian.Uint64(uuid[0:]) >> 16
+		return time.UnixMilli(int64(ms)).UTC()
+	default:
+		return time.Time{}
+	}
+	since := int64(timestamp) - gregorianOffset
+	return time.Unix(since/1e7, since%1e7*100).UTC()
+}
+
+// Validate returns an error unless the UUID is an RFC 9562 UUID of version
+// 1, 3, 4, 5, 6 or 7
+func (uuid UUID) Validate() error {
+	if uuid.Variant() != VariantRFC9562 {
+		return fmt.Errorf("UUIDs must be of the RFC 9562 variant, not %s", uuid.Variant())
+	}
+	switch uuid.Version() {
+	case 1, 3, 4, 5, 6, 7:
+		return nil
+	}
+	return fmt.Errorf("UUIDs of version %d are not supported", uuid.Version())
+}
+
+func setVersion(u *UUID, version byte) {
+	u[6] = (u[6] & 0x0f) | version<<4
+	u[8] = (u[8] & 0x3f) | 0x80 // Variant is 10
+}
+
+func readRandom(b []byte) {
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		log.Panic(err) // rand should never fail
+	}
+}
+
+func newHashUUID(h hash.Hash, namespace UUID, name string, version byte) (u UUID) {
+	h.Write(namespace[:])
+	h.Write([]byte(name))
+	copy(u[:], h.Sum(nil))
+	setVersion(&u, version)
+	return
+}
+
+// NewUUIDv3 creates a name-based UUID from the MD5 hash of the namespace
+// and name. NewUUIDv5 should be preferred.
+func NewUUIDv3(namespace UUID, name string) UUID {
+	return newHashUUID(md5.New(), namespace, name, 3)
+}
+
+// NewUUIDv5 creates a name-based UUID from the SHA-1 hash of the namespace
+// and name. The same namespace and name always create the same UUID.
+func NewUUIDv5(namespace UUID, name string) UUID {
+	return newHashUUID(sha1.New(), namespace, name, 5)
+}
+
+// NewUUIDv7 creates a time-ordered UUID from the current Unix time in
+// milliseconds followed by random bits. UUIDs created by NewUUIDv7 are
+// strictly increasing - see UUIDGenerator.
+func NewUUIDv7() UUID {
+	return mustUUID(DefaultUUIDGenerator().NewV7())
+}
+
+// gregorianOffset is the number of 100 nanosecond intervals between the
+// start of the Gregorian calendar (1582-10-15) and the Unix epoch
+const gregorianOffset = 122192928000000000
+
+// gregorianClock provides the timestamp, clock sequence and node of
+// version 1 and 6 UUIDs. The node is random, with the multicast bit set
+// as required by RFC 9562 section 6.10.
+var gregorianClock struct {
+	sync.Mutex
+	last     uint64
+	clockSeq uint16
+	node     [6]byte
+	init     bool
+}
+
+func gregorianTime() (timestamp uint64, clockSeq uint16, node [6]byte) {
+	gregorianClock.Lock()
+	defer gregorianClock.Unlock()
+	if !gregorianClock.init {
+		var b [8]byte
+		readRandom(b[:])
+		gregorianClock.clockSeq = binary.BigEndian.Uint16(b[:2]) & 0x3fff
+		copy(gregorianClock.node[:], b[2:])
+		gregorianClock.node[0] |= 0x01
+		gregorianClock.init = true
+	}
+	timestamp = uint64(time.Now().UnixNano()/100) + gregorianOffset
+	if timestamp <= gregorianClock.last {
+		// Keep timestamps unique within the resolution of the clock
+		timestamp = gregorianClock.last + 1
+	}
+	gregorianClock.last = timestamp
+	return timestamp, gregorianClock.clockSeq, gregorianClock.node
+}
+
+// NewUUIDv1 creates a UUID from the current Gregorian time, a clock
+// sequence and a random node ID
+func NewUUIDv1() (u UUID) {
+	timestamp, clockSeq, node := gregorianTime()
+	binary.BigEndian.PutUint32(u[0:], uint32(timestamp))
+	binary.BigEndian.PutUint16(u[4:], uint16(timestamp>>32))
+	binary.BigEndian.PutUint16(u[6:], uint16(timestamp>>48))
+	binary.BigEndian.PutUint16(u[8:], clockSeq)
+	copy(u[10:], node[:])
+	setVersion(&u, 1)
+	return
+}
+
+// NewUUIDv6 creates a UUID with the fields of a version 1 UUID, but with
+// the timestamp reordered so that UUIDs sort by time
+func NewUUIDv6() (u UUID) {
+	timestamp, clockSeq, node := gregorianTime()
+	binary.BigEndian.PutUint64(u[0:], timestamp<<4)
+	binary.BigEndian.PutUint16(u[6:], uint16(timestamp&0x0fff))
+	binary.BigEndian.PutUint16(u[8:], clockSeq)
+	copy(u[10:], node[:])
+	setVersion(&u, 6)
+	return
+}
@@ -0,0 +1,77 @@
+package fields
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestUUID_Versions(t *testing.T) {
+	tests := []struct {
+		uuid    UUID
+		version int
+	}{
+		{uuid: NewUUID(), version: 4},
+		{uuid: NewUUIDv1(), version: 1},
+		{uuid: NewUUIDv3(NamespaceDNS, "example.com"), version: 3},
+		{uuid: NewUUIDv5(NamespaceDNS, "example.com"), version: 5},
+		{uuid: NewUUIDv6(), version: 6},
+		{uuid: NewUUIDv7(), version: 7},
+	}
+	for _, test := range tests {
+		if test.uuid.Version() != test.version {
+			t.Errorf("unexpected version: %d != %d", test.uuid.Version(), test.version)
+		}
+		if test.uuid.Variant() != VariantRFC9562 {
+			t.Errorf("unexpected variant: %s", test.uuid.Variant())
+		}
+		if err := test.uuid.Validate(); err != nil {
+			t.Errorf("Validate should not error: %s", err)
+		}
+		if !test.uuid.Exists() {
+			t.Errorf("UUID version %d should exist", test.version)
+		}
+	}
+
+	// Name-based UUIDs are deterministic - values from RFC 9562 appendix A
+	if v5 := NewUUIDv5(NamespaceDNS, "www.example.com"); v5.String() != "2ed6657d-e927-568b-95e1-2665a8aea6a2" {
+		t.Errorf("unexpected v5 UUID: %s", v5)
+	}
+	if v3 := NewUUIDv3(NamespaceDNS, "www.example.com"); v3.String() != "5df41881-3aed-3515-88a7-2f4a814cf09e" {
+		t.Errorf("unexpected v3 UUID: %s", v3)
+	}
+
+	// Legacy v1 and v6 UUIDs from RFC 9562 appendix A
+	for _, s := range []string{
+		"c232ab00-9414-11ec-b3c8-9f6bdeced846",
+		"1ec9414c-232a-6b00-b3c8-9f6bdeced846",
+	} {
+		uuid, err := ParseUUID(s)
+		if err != nil {
+			t.Fatalf("ParseUUID should not error: %s", err)
+		}
+		if err := uuid.Validate(); err != nil {
+			t.Errorf("Validate should not error: %s", err)
+		}
+	}
+
+	invalid := []UUID{
+		{},
+		{6: 0x80, 8: 0x80},
+		{6: 0x40, 8: 0xc0},
+	}
+	for _, uuid := range invalid {
+		if err := uuid.Validate(); err == nil {
+			t.Errorf("Validate should error for %s", uuid)
+		}
+		if uuid.Exists() {
+			t.Errorf("%s should not exist", uuid)
+		}
+	}
+}
+
+func TestNewUUIDv6_Order(t *testing.T) {
+	a, b := NewUUIDv6(), NewUUIDv6()
+	if bytes.Compare(a[:], b[:]) >= 0 {
+		t.Errorf("v6 UUIDs should sort by time: %s >= %s", a, b)
+	}
+}